@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var (
+	adminAddr  = flag.String("admin-addr", "", "Admin HTTP server address for runtime control (default: co-located with -prometheus-addr)")
+	configFile = flag.String("config", "", "JSON file with concurrency/range-size/max-rps overrides, hot-reloaded on change")
+)
+
+// RuntimeConfig is the subset of benchmark parameters that can be changed
+// without restarting the process, either through the admin HTTP endpoints
+// or by editing -config on disk. Zero values are left alone so a partial
+// file only touches the fields it sets.
+type RuntimeConfig struct {
+	Concurrency int     `json:"concurrency,omitempty"`
+	RangeSize   int64   `json:"range_size,omitempty"`
+	MaxRPS      float64 `json:"max_rps,omitempty"`
+}
+
+// AdminServer exposes HTTP endpoints to steer a long-running benchmark —
+// changing concurrency, range-size, and max-rps, and pausing, resuming, or
+// aborting the current phase — so a 3+ hour steady-state run can be
+// corrected without killing and restarting the whole process.
+type AdminServer struct {
+	runner *BenchmarkRunner
+}
+
+// NewAdminServer builds an AdminServer bound to runner.
+func NewAdminServer(runner *BenchmarkRunner) *AdminServer {
+	return &AdminServer{runner: runner}
+}
+
+// Start registers the admin handlers on the default ServeMux, the same one
+// the Prometheus exporter serves /metrics from. When addr is empty or
+// matches -prometheus-addr, the handlers are simply reachable on the
+// already-running :9100 listener; otherwise a dedicated listener is started
+// on addr.
+func (a *AdminServer) Start(addr string) {
+	http.HandleFunc("/admin/concurrency", a.handleConcurrency)
+	http.HandleFunc("/admin/range-size", a.handleRangeSize)
+	http.HandleFunc("/admin/max-rps", a.handleMaxRPS)
+	http.HandleFunc("/admin/pause", a.handlePause)
+	http.HandleFunc("/admin/resume", a.handleResume)
+	http.HandleFunc("/admin/abort", a.handleAbort)
+
+	if addr == "" || addr == *prometheusAddr {
+		return
+	}
+
+	go func() {
+		a.runner.logger.Info("Starting admin server", "addr", addr)
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			a.runner.logger.Error("Admin server error", "err", err)
+		}
+	}()
+}
+
+func (a *AdminServer) handleConcurrency(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	v, err := strconv.Atoi(r.URL.Query().Get("value"))
+	if err != nil || v <= 0 {
+		http.Error(w, "value must be a positive integer", http.StatusBadRequest)
+		return
+	}
+	a.runner.SetConcurrency(v)
+	fmt.Fprintf(w, "concurrency set to %d\n", v)
+}
+
+func (a *AdminServer) handleRangeSize(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	v, err := strconv.ParseInt(r.URL.Query().Get("value"), 10, 64)
+	if err != nil || v <= 0 {
+		http.Error(w, "value must be a positive integer", http.StatusBadRequest)
+		return
+	}
+	a.runner.SetRangeSize(v)
+	fmt.Fprintf(w, "range-size set to %d\n", v)
+}
+
+func (a *AdminServer) handleMaxRPS(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	v, err := strconv.ParseFloat(r.URL.Query().Get("value"), 64)
+	if err != nil || v < 0 {
+		http.Error(w, "value must be a non-negative number", http.StatusBadRequest)
+		return
+	}
+	a.runner.SetMaxRPS(v)
+	fmt.Fprintf(w, "max-rps set to %.2f\n", v)
+}
+
+func (a *AdminServer) handlePause(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	a.runner.Pause()
+	fmt.Fprintln(w, "paused")
+}
+
+func (a *AdminServer) handleResume(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	a.runner.Resume()
+	fmt.Fprintln(w, "resumed")
+}
+
+func (a *AdminServer) handleAbort(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	a.runner.AbortPhase()
+	fmt.Fprintln(w, "current phase aborted")
+}
+
+func requirePost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+// effectiveRangeSize returns the admin-overridden range size fed to the
+// byte-rate safety limiter, falling back to the configured default.
+func (br *BenchmarkRunner) effectiveRangeSize() int64 {
+	if v := atomic.LoadInt64(&br.rangeSizeOverride); v > 0 {
+		return v
+	}
+	return br.config.RangeSize
+}
+
+// SetRangeSize overrides the range size used by applySafetyLimits, taking
+// effect on the next request issued by every endpoint pool. maxBytesLimiter
+// was sized for the startup -range-size, so its burst is resized alongside
+// the override to keep a single request's byte count from exceeding what
+// WaitN will accept.
+func (br *BenchmarkRunner) SetRangeSize(bytes int64) {
+	atomic.StoreInt64(&br.rangeSizeOverride, bytes)
+
+	br.limiterMu.Lock()
+	if br.maxBytesLimiter != nil && int(bytes) > br.maxBytesLimiter.Burst() {
+		br.maxBytesLimiter.SetBurst(int(bytes))
+	}
+	br.limiterMu.Unlock()
+
+	br.logger.Info("Admin: range-size override set", "bytes", bytes)
+}
+
+// SetConcurrency changes the target worker count per endpoint pool. Running
+// pools pick it up on their next rescale tick (see runScalingPool).
+func (br *BenchmarkRunner) SetConcurrency(n int) {
+	atomic.StoreInt64(&br.concurrencyTarget, int64(n))
+	br.logger.Info("Admin: concurrency target set", "concurrency", n)
+}
+
+// SetMaxRPS replaces the global requests-per-second safety limiter, so it
+// can be tightened, loosened, or enabled from a standing start (ratePerSec
+// <= 0 disables it, matching newLimiter's convention).
+func (br *BenchmarkRunner) SetMaxRPS(ratePerSec float64) {
+	br.limiterMu.Lock()
+	br.maxRPSLimiter = newLimiter(ratePerSec, 1)
+	br.limiterMu.Unlock()
+	br.logger.Info("Admin: max-rps set", "max_rps", ratePerSec)
+}
+
+func (br *BenchmarkRunner) isPaused() bool {
+	return atomic.LoadInt32(&br.paused) == 1
+}
+
+// Pause halts request issuance for the current phase until Resume is
+// called, without tearing down worker goroutines.
+func (br *BenchmarkRunner) Pause() {
+	atomic.StoreInt32(&br.paused, 1)
+	br.logger.Info("Admin: benchmark paused")
+}
+
+// Resume lifts a pause started by Pause.
+func (br *BenchmarkRunner) Resume() {
+	atomic.StoreInt32(&br.paused, 0)
+	br.logger.Info("Admin: benchmark resumed")
+}
+
+// waitWhilePaused blocks the calling worker while the run is paused,
+// returning false if ctx ends or the process is stopping before a resume.
+func (br *BenchmarkRunner) waitWhilePaused(ctx context.Context) bool {
+	for br.isPaused() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-br.stopChan:
+			return false
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	return true
+}
+
+// beginPhase installs a fresh abort channel for the phase about to start
+// and returns it for the caller's select loop.
+func (br *BenchmarkRunner) beginPhase() <-chan struct{} {
+	br.phaseAbortMu.Lock()
+	defer br.phaseAbortMu.Unlock()
+	ch := make(chan struct{})
+	br.phaseAbort = ch
+	return ch
+}
+
+// AbortPhase ends the current phase or ramp step immediately, as if its
+// timer had elapsed; the benchmark then proceeds to the next phase exactly
+// as it would on natural completion.
+func (br *BenchmarkRunner) AbortPhase() {
+	br.phaseAbortMu.Lock()
+	defer br.phaseAbortMu.Unlock()
+	if br.phaseAbort != nil {
+		close(br.phaseAbort)
+		br.phaseAbort = nil
+	}
+}
+
+// watchConfigFile watches path (if non-empty) with fsnotify and applies
+// concurrency/range-size/max-rps overrides from it on every write, so a
+// long steady-state run can be retuned by editing a file instead of curling
+// the admin API. Reload outcomes are counted via
+// r2_bench_config_reloads_total{result=...}.
+func watchConfigFile(path string, runner *BenchmarkRunner) {
+	if path == "" {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		runner.logger.Error("Admin: failed to start config watcher", "err", err)
+		return
+	}
+
+	if err := watcher.Add(path); err != nil {
+		runner.logger.Error("Admin: failed to watch config file", "path", path, "err", err)
+		watcher.Close()
+		return
+	}
+
+	runner.logger.Info("Watching for runtime config changes", "path", path)
+	applyConfigFile(path, runner)
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					applyConfigFile(path, runner)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				runner.logger.Error("Admin: config watcher error", "err", err)
+			}
+		}
+	}()
+}
+
+func applyConfigFile(path string, runner *BenchmarkRunner) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		runner.logger.Error("Admin: failed to read config file", "path", path, "err", err)
+		runner.promExporter.RecordConfigReload("error")
+		return
+	}
+
+	var cfg RuntimeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		runner.logger.Error("Admin: failed to parse config file", "path", path, "err", err)
+		runner.promExporter.RecordConfigReload("error")
+		return
+	}
+
+	if cfg.Concurrency > 0 {
+		runner.SetConcurrency(cfg.Concurrency)
+	}
+	if cfg.RangeSize > 0 {
+		runner.SetRangeSize(cfg.RangeSize)
+	}
+	if cfg.MaxRPS > 0 {
+		runner.SetMaxRPS(cfg.MaxRPS)
+	}
+
+	runner.logger.Info("Admin: reloaded config", "path", path)
+	runner.promExporter.RecordConfigReload("success")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// ConcurrencyStep records the outcome of a single ramp step, used both to
+// drive the controller's next decision and as a reproducible search trace.
+type ConcurrencyStep struct {
+	Timestamp      time.Time `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Concurrency    int       `parquet:"name=concurrency, type=INT32"`
+	ThroughputBps  float64   `parquet:"name=throughput_bps, type=DOUBLE"`
+	LatencyP50Ms   float64   `parquet:"name=latency_p50_ms, type=DOUBLE"`
+	LatencyP99Ms   float64   `parquet:"name=latency_p99_ms, type=DOUBLE"`
+	ErrorRate      float64   `parquet:"name=error_rate, type=DOUBLE"`
+	Gradient       float64   `parquet:"name=gradient, type=DOUBLE"`
+	LatencyPenalty float64   `parquet:"name=latency_penalty, type=DOUBLE"`
+	Accepted       bool      `parquet:"name=accepted, type=BOOLEAN"`
+}
+
+// ConcurrencyController implements a Little's-law / gradient search for the
+// concurrency level that maximizes throughput while keeping p99 latency and
+// error rate within configured SLOs. It increases concurrency by
+// RampStepSize while the gradient signal stays healthy, backs off using AIMD
+// (halving the increment) on regression, and stops after three consecutive
+// steps fail to improve on the best throughput seen so far.
+type ConcurrencyController struct {
+	gradientThreshold float64
+	maxErrorRate      float64
+	minRTTMs          float64
+	stepSize          int
+
+	bestThroughput    float64
+	bestConcurrency   int
+	nonImproveStreak  int
+	trace             []ConcurrencyStep
+}
+
+// NewConcurrencyController builds a controller seeded from the benchmark
+// config's SLOs and ramp step size.
+func NewConcurrencyController(cfg *Config) *ConcurrencyController {
+	return &ConcurrencyController{
+		gradientThreshold: cfg.GradientThreshold,
+		maxErrorRate:      cfg.MaxErrorRate,
+		minRTTMs:          cfg.MinRTTMs,
+		stepSize:          cfg.RampStepSize,
+	}
+}
+
+// Evaluate records the measured outcome of the given concurrency level and
+// returns the concurrency to test next plus whether the search should stop.
+func (c *ConcurrencyController) Evaluate(concurrency int, throughputBps, p50Ms, p99Ms, errorRate float64) (next int, done bool) {
+	latencyPenalty := 1.0
+	if p99Ms > 0 && c.minRTTMs > 0 {
+		latencyPenalty = c.minRTTMs / p99Ms
+	}
+
+	gradient := 1.0
+	if c.bestThroughput > 0 {
+		gradient = throughputBps / c.bestThroughput
+	}
+
+	accepted := errorRate < c.maxErrorRate && gradient*latencyPenalty > c.gradientThreshold
+
+	step := ConcurrencyStep{
+		Timestamp:      time.Now(),
+		Concurrency:    concurrency,
+		ThroughputBps:  throughputBps,
+		LatencyP50Ms:   p50Ms,
+		LatencyP99Ms:   p99Ms,
+		ErrorRate:      errorRate,
+		Gradient:       gradient,
+		LatencyPenalty: latencyPenalty,
+		Accepted:       accepted,
+	}
+	c.trace = append(c.trace, step)
+
+	if accepted && throughputBps > c.bestThroughput {
+		c.bestThroughput = throughputBps
+		c.bestConcurrency = concurrency
+		c.nonImproveStreak = 0
+	} else {
+		c.nonImproveStreak++
+	}
+
+	if c.nonImproveStreak >= 3 {
+		return c.bestConcurrency, true
+	}
+
+	if !accepted {
+		// AIMD backoff: halve the increment instead of the concurrency
+		// level itself, so the search narrows in on the knee rather
+		// than oscillating wildly.
+		c.stepSize = maxInt(c.stepSize/2, 1)
+		return maxInt(concurrency-c.stepSize, 1), false
+	}
+
+	return concurrency + c.stepSize, false
+}
+
+// BestConcurrency returns the concurrency level with the highest observed
+// throughput that still satisfied the SLOs.
+func (c *ConcurrencyController) BestConcurrency() int {
+	return c.bestConcurrency
+}
+
+// Trace returns the full search history, in evaluation order, for
+// persistence alongside the chosen result.
+func (c *ConcurrencyController) Trace() []ConcurrencyStep {
+	return c.trace
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// percentile returns the p-th percentile (0-100) of a slice of latencies in
+// milliseconds: the element at index floor(p/100 * (n-1)) of the sorted
+// input, without interpolating between neighbours. The input is sorted
+// in-place.
+func percentile(latenciesMs []float64, p float64) float64 {
+	if len(latenciesMs) == 0 {
+		return 0
+	}
+	sort.Float64s(latenciesMs)
+	idx := int(p / 100 * float64(len(latenciesMs)-1))
+	return latenciesMs[idx]
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"sync"
+	"time"
+
+	"r2-bench/pkg/download"
+)
+
+var (
+	dlManagerThreshold   = flag.Int64("dl-manager-threshold", 20*1024*1024, "Ranges larger than this many bytes are fetched via the concurrent download manager instead of one GetObjectRange call")
+	dlManagerPartSize    = flag.Int64("dl-manager-part-size", download.DefaultPartSize, "Sub-range size the download manager fetches per concurrent reader")
+	dlManagerConcurrency = flag.Int("dl-manager-concurrency", download.DefaultConcurrency, "Number of sub-ranges the download manager fetches in parallel per request")
+)
+
+// downloadBufferPool reuses the byte slices the download manager writes
+// parts into, so a steady stream of large-range requests doesn't allocate
+// a fresh full-range buffer on every request.
+var downloadBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, download.DefaultPartSize*2)
+		return &buf
+	},
+}
+
+// executeDownloadManagerRequest fetches a large range via br.downloadManager
+// instead of one blocking GetObjectRange call, and records each sub-range
+// fetch as its own RequestResult row (sharing requestID/scheduledAt/thread
+// so they can be grouped back into the parent request) rather than
+// collapsing them into a single row, so head-of-line stalls on one part
+// are visible instead of averaged away.
+func (br *BenchmarkRunner) executeDownloadManagerRequest(ctx context.Context, ep *Endpoint, threadID, concurrency int, scheduledAt time.Time, requestID, objectKey string, rangeStart, rangeLen int64, logger *slog.Logger) {
+	bufPtr := downloadBufferPool.Get().(*[]byte)
+	buf := *bufPtr
+	if int64(cap(buf)) < rangeLen {
+		buf = make([]byte, rangeLen)
+	} else {
+		buf = buf[:rangeLen]
+	}
+
+	parts := br.downloadManager.Download(ctx, ep.Client, objectKey, rangeStart, rangeLen, download.BufferWriterAt(buf))
+
+	*bufPtr = buf
+	downloadBufferPool.Put(bufPtr)
+
+	for _, part := range parts {
+		result := RequestResult{
+			Timestamp:      scheduledAt,
+			ScheduledAt:    scheduledAt,
+			ThreadID:       threadID,
+			ConnID:         threadID % concurrency,
+			ObjectKey:      objectKey,
+			RangeStart:     part.RangeStart,
+			RangeLen:       part.RangeLen,
+			PartNumber:     part.PartNumber,
+			Bytes:          part.Bytes,
+			LatencyMs:      float64(part.Latency.Microseconds()) / 1000.0,
+			HTTPStatus:     200,
+			InstanceType:   br.config.InstanceType,
+			Concurrency:    concurrency,
+			WorkloadType:   br.generator.Name(),
+			WorkloadParams: br.generator.Params(),
+			Endpoint:       ep.Label,
+			RequestID:      requestID,
+			RunID:          br.config.RunID,
+		}
+
+		if part.Err != nil {
+			result.ErrMsg = part.Err.Error()
+			result.HTTPStatus = 500
+			logger.Error("Download manager part failed", "part", part.PartNumber, "err", part.Err)
+		} else if br.shouldSampleRequestLog() {
+			logger.Debug("Download manager part completed", "part", part.PartNumber, "latency_ms", result.LatencyMs, "bytes", result.Bytes)
+		}
+
+		br.sendResult(result)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"r2-bench/instances/objstore"
+	"r2-bench/pkg/results"
+	"r2-bench/storage"
+)
+
+// repeatedFlag collects every occurrence of a flag passed multiple times on
+// the command line, e.g. `-url a -url b` -> []string{"a", "b"}.
+type repeatedFlag []string
+
+func (r *repeatedFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *repeatedFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+// Endpoint bundles everything needed to drive one storage backend for the
+// duration of a comparative run: its client, a human-readable label used
+// for the `endpoint` metric/Parquet label, and its own Parquet writer so
+// side-by-side runs don't interleave rows. resultChan feeds the writer's
+// Ingest goroutine; ingestDone reports Ingest's return value once
+// resultChan is closed during cleanup.
+type Endpoint struct {
+	Label         string
+	Client        StorageClient
+	ParquetWriter *results.ParquetWriter
+	resultChan    chan results.Record
+	ingestDone    chan error
+}
+
+// EndpointSpec is one entry in an -endpoints-config file: a label for
+// metrics/Parquet output plus the objstore.Config describing which driver
+// to load and how to configure it.
+type EndpointSpec struct {
+	Label           string `yaml:"label"`
+	objstore.Config `yaml:",inline"`
+}
+
+// EndpointsConfigFile is the YAML/JSON shape accepted by -endpoints-config:
+// a list of endpoints, each naming an objstore driver and its settings, so
+// a comparative run can mix S3-compatible and non-S3 backends (GCS, Azure,
+// the local filesystem) without a CLI flag per provider.
+type EndpointsConfigFile struct {
+	Endpoints []EndpointSpec `yaml:"endpoints"`
+}
+
+// loadEndpointsConfig reads and validates an -endpoints-config file.
+func loadEndpointsConfig(path string) ([]EndpointSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("endpoints config: read %s: %w", path, err)
+	}
+	var file EndpointsConfigFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("endpoints config: parse %s: %w", path, err)
+	}
+	if len(file.Endpoints) == 0 {
+		return nil, fmt.Errorf("endpoints config: %s defines no endpoints", path)
+	}
+	for i, ep := range file.Endpoints {
+		if ep.Label == "" {
+			return nil, fmt.Errorf("endpoints config: entry %d: label is required", i)
+		}
+		if ep.Driver == "" {
+			return nil, fmt.Errorf("endpoints config: entry %d (%s): driver is required", i, ep.Label)
+		}
+	}
+	return file.Endpoints, nil
+}
+
+// builtEndpoint is the label+client pair produced by either endpoint
+// construction path, before the Parquet writer and ingest goroutine common
+// to both are wired up.
+type builtEndpoint struct {
+	label  string
+	client objstore.Bucket
+}
+
+// buildEndpoints constructs one Endpoint per configured backend. If
+// -endpoints-config is set, every endpoint (including its driver) comes
+// from that file, which is the only way to benchmark a non-S3-compatible
+// provider (GCS, Azure, filesystem) or MinIO; otherwise it falls back to
+// one Endpoint per -url/-bucket pair, resolved to the "r2" or "s3"
+// objstore driver the same way this flow always has. Every driver is
+// wrapped in a storage.InstrumentedBucket before use, so per-operation
+// metrics are recorded automatically without the worker loop having to
+// call PrometheusExporter.RecordRequest itself. runID and instanceType are
+// stamped into each endpoint's Parquet output path
+// (results/<run_id>/<instance_type>/<start_ts>.parquet).
+func buildEndpoints(urls, buckets, r2Accounts, r2AccessKeys, r2SecretKeys []string, outputDir, runID, instanceType string) ([]*Endpoint, error) {
+	var built []builtEndpoint
+
+	if *endpointsConfigPath != "" {
+		specs, err := loadEndpointsConfig(*endpointsConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			client, err := objstore.New(spec.Config)
+			if err != nil {
+				return nil, fmt.Errorf("endpoint %s: %w", spec.Label, err)
+			}
+			built = append(built, builtEndpoint{label: spec.Label, client: client})
+		}
+	} else {
+		if len(urls) == 0 {
+			return nil, fmt.Errorf("at least one -url is required (or set -endpoints-config)")
+		}
+		if len(buckets) != len(urls) {
+			return nil, fmt.Errorf("got %d -url flags but %d -bucket flags; they must be repeated in pairs", len(urls), len(buckets))
+		}
+		for i, u := range urls {
+			bucket := buckets[i]
+			label := endpointLabel(u, bucket, i)
+
+			client, err := buildClient(u, bucket, i, r2Accounts, r2AccessKeys, r2SecretKeys)
+			if err != nil {
+				return nil, fmt.Errorf("endpoint %s: %w", label, err)
+			}
+			built = append(built, builtEndpoint{label: label, client: client})
+		}
+	}
+
+	bucketMetrics := storage.NewBucketMetrics(storage.BucketMetricsOptions{NativeHistograms: *nativeHistograms})
+
+	endpoints := make([]*Endpoint, 0, len(built))
+	for _, b := range built {
+		instrumented := storage.NewInstrumentedBucket(b.client, bucketMetrics, runID, instanceType, b.label)
+
+		writer, err := results.NewParquetWriter(results.WriterConfig{
+			BaseDir:        fmt.Sprintf("%s/%s", outputDir, b.label),
+			RunID:          runID,
+			InstanceType:   instanceType,
+			MaxRows:        *parquetMaxRows,
+			MaxBytes:       *parquetMaxBytes,
+			RotateInterval: *parquetRotate,
+			Compression:    results.Compression(*parquetCompression),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: failed to create Parquet writer: %w", b.label, err)
+		}
+
+		resultChan := make(chan results.Record, 1000)
+		ingestDone := make(chan error, 1)
+		go func() {
+			ingestDone <- writer.Ingest(context.Background(), resultChan)
+		}()
+
+		endpoints = append(endpoints, &Endpoint{
+			Label:         b.label,
+			Client:        instrumented,
+			ParquetWriter: writer,
+			resultChan:    resultChan,
+			ingestDone:    ingestDone,
+		})
+	}
+
+	return endpoints, nil
+}
+
+// buildClient resolves a -url/-bucket pair to an objstore Bucket, the way
+// this flow always has: R2 by the URL's hostname, S3 by default. Anything
+// beyond those two providers needs -endpoints-config instead.
+func buildClient(url, bucket string, idx int, r2Accounts, r2AccessKeys, r2SecretKeys []string) (objstore.Bucket, error) {
+	if isR2Endpoint(url) {
+		accountID := valueAt(r2Accounts, idx, os.Getenv("R2_ACCOUNT_ID"))
+		accessKeyID := valueAt(r2AccessKeys, idx, os.Getenv("R2_ACCESS_KEY_ID"))
+		secretAccessKey := valueAt(r2SecretKeys, idx, os.Getenv("R2_SECRET_ACCESS_KEY"))
+
+		if accountID == "" || accessKeyID == "" || secretAccessKey == "" {
+			return nil, fmt.Errorf("R2 credentials not found (env vars or -r2-account/-r2-access-key/-r2-secret-key)")
+		}
+
+		return objstore.NewR2Bucket(accountID, accessKeyID, secretAccessKey, bucket)
+	}
+
+	region := extractRegionFromURL(url)
+	return objstore.NewS3Bucket(region, bucket)
+}
+
+// valueAt returns values[idx] when present, falling back to def. It lets a
+// single shared credential (e.g. one env var) cover every endpoint when
+// per-endpoint credential flags aren't repeated.
+func valueAt(values []string, idx int, def string) string {
+	if idx < len(values) {
+		return values[idx]
+	}
+	return def
+}
+
+// endpointLabel derives a short, filesystem- and Prometheus-label-safe name
+// for an endpoint from its URL and bucket.
+func endpointLabel(url, bucket string, idx int) string {
+	label := url
+	if isR2Endpoint(url) {
+		label = "r2"
+	} else {
+		label = fmt.Sprintf("s3-%s", extractRegionFromURL(url))
+	}
+	return fmt.Sprintf("%s-%s", label, bucket)
+}
+
+var (
+	urlFlags         repeatedFlag
+	bucketFlags      repeatedFlag
+	r2AccountFlags   repeatedFlag
+	r2AccessKeyFlags repeatedFlag
+	r2SecretKeyFlags repeatedFlag
+
+	endpointsConfigPath = flag.String("endpoints-config", "", "Path to a YAML/JSON file defining endpoints by objstore driver (s3, r2, gcs, azure, filesystem, minio); overrides -url/-bucket/-r2-* when set")
+	nativeHistograms    = flag.Bool("native-histograms", false, "Use Prometheus native (sparse) histograms for per-operation bucket latency instead of classic exponential buckets")
+)
+
+func init() {
+	flag.Var(&urlFlags, "url", "Storage endpoint URL (r2 or s3); repeat for multiple endpoints")
+	flag.Var(&bucketFlags, "bucket", "Bucket name; repeat to pair with each -url")
+	flag.Var(&r2AccountFlags, "r2-account", "R2 account ID; repeat to pair with each -url (falls back to R2_ACCOUNT_ID)")
+	flag.Var(&r2AccessKeyFlags, "r2-access-key", "R2 access key ID; repeat to pair with each -url (falls back to R2_ACCESS_KEY_ID)")
+	flag.Var(&r2SecretKeyFlags, "r2-secret-key", "R2 secret access key; repeat to pair with each -url (falls back to R2_SECRET_ACCESS_KEY)")
+}
@@ -0,0 +1,210 @@
+//go:build freebsd || netbsd || openbsd
+
+package instances
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bsdStatsProvider implements SystemStatsProvider for FreeBSD/NetBSD/
+// OpenBSD via the same sysctl MIBs and command-line tools those systems'
+// own `top`/`systat` are built on. There is no /proc here (FreeBSD's
+// linprocfs/linux_proc.go's format aside, it isn't mounted by default), so
+// this shells out rather than assuming Linux's text files exist.
+type bsdStatsProvider struct{}
+
+func newPlatformStatsProvider() SystemStatsProvider {
+	return bsdStatsProvider{}
+}
+
+func (bsdStatsProvider) Capabilities() StatsCapabilities {
+	return StatsCapabilities{CPU: true, Memory: true, Network: true, IRQ: true}
+}
+
+func (p bsdStatsProvider) CollectSystemStats() (*SystemStats, error) {
+	stats := &SystemStats{}
+
+	cpuUtil, err := p.cpuUtilization()
+	if err != nil {
+		return nil, fmt.Errorf("cpu utilization: %w", err)
+	}
+	stats.CPUUtilization = cpuUtil
+
+	memUsage, err := p.memoryUsage()
+	if err != nil {
+		return nil, fmt.Errorf("memory usage: %w", err)
+	}
+	stats.MemoryUsage = memUsage
+
+	netStats, err := p.networkStats()
+	if err != nil {
+		return nil, fmt.Errorf("network stats: %w", err)
+	}
+	stats.NetworkStats = netStats
+
+	irqRate, err := p.irqRate()
+	if err != nil {
+		return nil, fmt.Errorf("irq rate: %w", err)
+	}
+	stats.IRQRate = irqRate
+
+	return stats, nil
+}
+
+// cpuUtilization reads kern.cp_time (cumulative user/nice/system/interrupt/
+// idle clock ticks) twice, a short interval apart, and derives utilization
+// from the delta - the same counters FreeBSD's own top(1) uses.
+func (p bsdStatsProvider) cpuUtilization() (float64, error) {
+	before, err := readCPTime()
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(100 * time.Millisecond)
+	after, err := readCPTime()
+	if err != nil {
+		return 0, err
+	}
+
+	var totalDelta, idleDelta int64
+	for i := range before {
+		d := after[i] - before[i]
+		totalDelta += d
+	}
+	// kern.cp_time order is user, nice, system, interrupt, idle.
+	if len(after) == 5 {
+		idleDelta = after[4] - before[4]
+	}
+	if totalDelta == 0 {
+		return 0, nil
+	}
+	return float64(totalDelta-idleDelta) / float64(totalDelta) * 100, nil
+}
+
+func readCPTime() ([]int64, error) {
+	out, err := exec.Command("sysctl", "-n", "kern.cp_time").Output()
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(out))
+	times := make([]int64, len(fields))
+	for i, f := range fields {
+		times[i], _ = strconv.ParseInt(f, 10, 64)
+	}
+	return times, nil
+}
+
+// memoryUsage combines hw.physmem (total bytes) with the free-page count
+// FreeBSD tracks in vm.stats.vm.v_free_count.
+func (p bsdStatsProvider) memoryUsage() (float64, error) {
+	total, err := sysctlInt64("hw.physmem")
+	if err != nil || total == 0 {
+		return 0, err
+	}
+
+	freePages, err := sysctlInt64("vm.stats.vm.v_free_count")
+	if err != nil {
+		return 0, nil // not every BSD exposes this MIB; report 0 rather than erroring
+	}
+	pageSize, err := sysctlInt64("hw.pagesize")
+	if err != nil || pageSize == 0 {
+		pageSize = 4096
+	}
+
+	available := freePages * pageSize
+	used := total - available
+	return float64(used) / float64(total) * 100, nil
+}
+
+func sysctlInt64(mib string) (int64, error) {
+	out, err := exec.Command("sysctl", "-n", mib).Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
+
+// networkStats parses `netstat -ibn`, the same link-layer counter dump
+// used on Darwin.
+func (p bsdStatsProvider) networkStats() (*NetworkStats, error) {
+	out, err := exec.Command("netstat", "-ibn").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &NetworkStats{}
+	lines := strings.Split(string(out), "\n")
+	if len(lines) == 0 {
+		return stats, nil
+	}
+
+	header := strings.Fields(lines[0])
+	col := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		return -1
+	}
+	nameCol, ibytesCol, obytesCol := col("Name"), col("Ibytes"), col("Obytes")
+	ipktsCol, opktsCol := col("Ipkts"), col("Opkts")
+	if nameCol < 0 || ibytesCol < 0 || obytesCol < 0 {
+		return stats, nil
+	}
+
+	seen := map[string]bool{}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) <= ibytesCol || len(fields) <= obytesCol {
+			continue
+		}
+		name := fields[nameCol]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		nic := NICStats{Name: name}
+		nic.BytesReceived, _ = strconv.ParseInt(fields[ibytesCol], 10, 64)
+		nic.BytesSent, _ = strconv.ParseInt(fields[obytesCol], 10, 64)
+		if ipktsCol >= 0 && len(fields) > ipktsCol {
+			nic.PacketsReceived, _ = strconv.ParseInt(fields[ipktsCol], 10, 64)
+		}
+		if opktsCol >= 0 && len(fields) > opktsCol {
+			nic.PacketsSent, _ = strconv.ParseInt(fields[opktsCol], 10, 64)
+		}
+		stats.Interfaces = append(stats.Interfaces, nic)
+	}
+	if len(stats.Interfaces) > 0 {
+		primary := stats.Interfaces[0]
+		stats.BytesReceived = primary.BytesReceived
+		stats.PacketsReceived = primary.PacketsReceived
+		stats.BytesSent = primary.BytesSent
+		stats.PacketsSent = primary.PacketsSent
+	}
+
+	return stats, nil
+}
+
+// irqRate reads the rate column of the "Total" line `vmstat -i` prints
+// (the BSD equivalent of /proc/interrupts): interrupts per second across
+// every source, averaged by vmstat since boot.
+func (p bsdStatsProvider) irqRate() (float64, error) {
+	out, err := exec.Command("vmstat", "-i").Output()
+	if err != nil {
+		return 0, nil // not fatal: some BSDs restrict this to root
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "Total" {
+			rate, _ := strconv.ParseFloat(fields[len(fields)-1], 64)
+			return rate, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,307 @@
+package instances
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContainerStats is cgroup-relative CPU, memory, and network usage, as
+// seen from inside a container (ECS, EKS, Fargate, self-managed Docker).
+// It exists alongside SystemStats because /proc/stat and /proc/meminfo -
+// what SystemStats is built from - reflect the *host*, not the
+// container's cgroup limits; CPUUtilization and MemoryUsage are
+// misleading for a process that only owns a fraction of the host.
+type ContainerStats struct {
+	CgroupVersion int // 1 or 2, whichever CgroupMonitor detected
+
+	// CPUUsagePct is relative to CPUQuotaCores when a quota is set, and
+	// to the host's CPU count otherwise - see CgroupMonitor.Sample.
+	CPUUsagePct   float64
+	CPUQuotaCores float64 // 0 means no quota is set
+
+	MemoryUsageBytes int64
+	MemoryLimitBytes int64 // 0 means no limit is set
+	MemoryUsagePct   float64
+
+	// NetworkStats is read from /proc/self/net/dev rather than the host's
+	// /proc/net/dev, so it only ever reports the container's own network
+	// namespace's interfaces.
+	NetworkStats *NetworkStats
+}
+
+// CgroupMonitor samples a container's own cgroup CPU/memory accounting
+// and its network namespace's interface counters, mirroring crunchstat's
+// approach of reading per-cgroup stats and per-PID /proc/<pid>/net/dev
+// instead of the host-wide files EC2Monitor's SystemStatsProvider reads.
+type CgroupMonitor struct {
+	version       int
+	cpuStatPath   string // cpu.stat (v2) or cpuacct.usage (v1)
+	cpuQuotaPath  string // cpu.max (v2) or cpu.cfs_quota_us (v1)
+	cpuPeriodPath string // only set on v1; v2 packs both into cpu.max
+	memUsagePath  string
+	memLimitPath  string
+	netDevPath    string
+
+	mu        sync.Mutex
+	prevUsage int64
+	prevAt    time.Time
+}
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	selfNetDev = "/proc/self/net/dev"
+
+	// v1NoLimitMin is a threshold, not the exact sentinel cgroup v1 uses
+	// for "no memory limit" (it varies by page size/arch) - anything this
+	// large is implausible as a real limit, so treat it as unlimited.
+	v1NoLimitMin = 1 << 62
+)
+
+// NewCgroupMonitor detects whether the calling process is running under
+// cgroup v1 or v2 and returns a monitor for it. It returns an error when
+// neither hierarchy is found under /sys/fs/cgroup, e.g. on a non-Linux
+// host. The probed files also exist at the root of a host's own
+// hierarchy, so a Linux host running outside any container gets a monitor
+// too; its Sample reads the root cgroup's files and returns an error
+// where one of them is absent (a cgroup v2 root has no cpu.max).
+func NewCgroupMonitor() (*CgroupMonitor, error) {
+	if _, err := os.Stat(cgroupRoot + "/cgroup.controllers"); err == nil {
+		return &CgroupMonitor{
+			version:      2,
+			cpuStatPath:  cgroupRoot + "/cpu.stat",
+			cpuQuotaPath: cgroupRoot + "/cpu.max",
+			memUsagePath: cgroupRoot + "/memory.current",
+			memLimitPath: cgroupRoot + "/memory.max",
+			netDevPath:   selfNetDev,
+		}, nil
+	}
+
+	if _, err := os.Stat(cgroupRoot + "/cpuacct/cpuacct.usage"); err == nil {
+		return &CgroupMonitor{
+			version:       1,
+			cpuStatPath:   cgroupRoot + "/cpuacct/cpuacct.usage",
+			cpuQuotaPath:  cgroupRoot + "/cpu/cpu.cfs_quota_us",
+			cpuPeriodPath: cgroupRoot + "/cpu/cpu.cfs_period_us",
+			memUsagePath:  cgroupRoot + "/memory/memory.usage_in_bytes",
+			memLimitPath:  cgroupRoot + "/memory/memory.limit_in_bytes",
+			netDevPath:    selfNetDev,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("instances: no cgroup v1 or v2 hierarchy found under %s", cgroupRoot)
+}
+
+// Sample reads the current cgroup CPU/memory accounting and the
+// container's own network namespace counters. CPUUsagePct is computed
+// against the delta since the previous Sample call, the same approach
+// Sampler uses for host-wide stats; the first call after construction has
+// no previous reading to diff against and reports 0.
+func (m *CgroupMonitor) Sample() (*ContainerStats, error) {
+	usageUsec, quotaCores, err := m.readCPU()
+	if err != nil {
+		return nil, fmt.Errorf("instances: read cgroup cpu stats: %w", err)
+	}
+	memUsage, memLimit, err := m.readMemory()
+	if err != nil {
+		return nil, fmt.Errorf("instances: read cgroup memory stats: %w", err)
+	}
+	netStats, err := readContainerNetDev(m.netDevPath)
+	if err != nil {
+		return nil, fmt.Errorf("instances: read container net dev: %w", err)
+	}
+
+	stats := &ContainerStats{
+		CgroupVersion:    m.version,
+		CPUQuotaCores:    quotaCores,
+		MemoryUsageBytes: memUsage,
+		MemoryLimitBytes: memLimit,
+		NetworkStats:     netStats,
+	}
+	if memLimit > 0 {
+		stats.MemoryUsagePct = float64(memUsage) / float64(memLimit) * 100
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	prevUsage, prevAt := m.prevUsage, m.prevAt
+	m.prevUsage, m.prevAt = usageUsec, now
+	m.mu.Unlock()
+
+	if !prevAt.IsZero() {
+		elapsedUsec := now.Sub(prevAt).Microseconds()
+		if elapsedUsec > 0 {
+			usageDelta := usageUsec - prevUsage
+			if usageDelta < 0 {
+				usageDelta = 0
+			}
+			utilFraction := float64(usageDelta) / float64(elapsedUsec)
+			if quotaCores > 0 {
+				stats.CPUUsagePct = utilFraction / quotaCores * 100
+			} else {
+				// No quota set: fall back to wall-clock CPU count, same
+				// as the host-relative SystemStats.CPUUtilization would.
+				stats.CPUUsagePct = utilFraction / float64(runtime.NumCPU()) * 100
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// readCPU returns cumulative CPU usage in microseconds and the quota, in
+// cores, the cgroup is allotted (0 if unlimited).
+func (m *CgroupMonitor) readCPU() (usageUsec int64, quotaCores float64, err error) {
+	if m.version == 2 {
+		return m.readCPUv2()
+	}
+	return m.readCPUv1()
+}
+
+// readCPUv2 reads usage_usec out of cpu.stat and the quota/period pair out
+// of cpu.max, which packs both into one file as "<quota> <period>" or
+// "max <period>" when no quota is set.
+func (m *CgroupMonitor) readCPUv2() (usageUsec int64, quotaCores float64, err error) {
+	file, err := os.Open(m.cpuStatPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			usageUsec, _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+
+	data, err := os.ReadFile(m.cpuQuotaPath)
+	if err != nil {
+		return usageUsec, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 2 && fields[0] != "max" {
+		quota, _ := strconv.ParseFloat(fields[0], 64)
+		period, _ := strconv.ParseFloat(fields[1], 64)
+		if period > 0 {
+			quotaCores = quota / period
+		}
+	}
+	return usageUsec, quotaCores, nil
+}
+
+// readCPUv1 reads cpuacct.usage (nanoseconds) and the cfs_quota_us/
+// cfs_period_us pair, where cfs_quota_us is -1 when no quota is set.
+func (m *CgroupMonitor) readCPUv1() (usageUsec int64, quotaCores float64, err error) {
+	usageNs, err := readInt64File(m.cpuStatPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	usageUsec = usageNs / 1000
+
+	quota, err := readInt64File(m.cpuQuotaPath)
+	if err != nil {
+		return usageUsec, 0, err
+	}
+	period, err := readInt64File(m.cpuPeriodPath)
+	if err != nil {
+		return usageUsec, 0, err
+	}
+	if quota > 0 && period > 0 {
+		quotaCores = float64(quota) / float64(period)
+	}
+	return usageUsec, quotaCores, nil
+}
+
+// readMemory returns current memory usage and limit, in bytes (limit 0
+// means unlimited).
+func (m *CgroupMonitor) readMemory() (usage, limit int64, err error) {
+	usage, err = readInt64File(m.memUsagePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if m.version == 2 {
+		data, err := os.ReadFile(m.memLimitPath)
+		if err != nil {
+			return usage, 0, err
+		}
+		text := strings.TrimSpace(string(data))
+		if text == "max" {
+			return usage, 0, nil
+		}
+		limit, _ = strconv.ParseInt(text, 10, 64)
+		return usage, limit, nil
+	}
+
+	limit, err = readInt64File(m.memLimitPath)
+	if err != nil {
+		return usage, 0, err
+	}
+	if limit >= v1NoLimitMin {
+		limit = 0
+	}
+	return usage, limit, nil
+}
+
+// readInt64File reads a file containing a single integer, the common
+// format for cgroup stat files like cpuacct.usage or memory.current.
+func readInt64File(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readContainerNetDev parses /proc/self/net/dev, the same format
+// readNICCounters reads for the host-wide /proc/net/dev, but scoped to
+// the calling process's own network namespace - which is all a
+// containerized process ever sees, so there's no delta-rate tracking
+// here the way Sampler does for the host.
+func readContainerNetDev(path string) (*NetworkStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // inter-|   receive ...
+	scanner.Scan() // face  |bytes packets errs ...
+
+	stats := &NetworkStats{}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 17 {
+			continue
+		}
+		name := strings.TrimSuffix(fields[0], ":")
+		parse := func(i int) int64 {
+			v, _ := strconv.ParseInt(fields[i], 10, 64)
+			return v
+		}
+		nic := NICStats{
+			Name:            name,
+			BytesReceived:   parse(1),
+			PacketsReceived: parse(2),
+			BytesSent:       parse(9),
+			PacketsSent:     parse(10),
+		}
+		stats.Interfaces = append(stats.Interfaces, nic)
+
+		if name != "lo" {
+			stats.BytesReceived += nic.BytesReceived
+			stats.PacketsReceived += nic.PacketsReceived
+			stats.BytesSent += nic.BytesSent
+			stats.PacketsSent += nic.PacketsSent
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,196 @@
+//go:build darwin
+
+package instances
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// darwinStatsProvider implements SystemStatsProvider by shelling out to the
+// same command-line tools Activity Monitor is built on: `top` for CPU,
+// `vm_stat`/`sysctl` for memory, and `netstat` for per-NIC counters.
+// macOS has no /proc, and reading the equivalent Mach host_statistics
+// counters requires cgo, so - like gopsutil's own darwin backends for
+// tools that don't want a cgo dependency - this parses command output
+// instead.
+type darwinStatsProvider struct{}
+
+func newPlatformStatsProvider() SystemStatsProvider {
+	return darwinStatsProvider{}
+}
+
+func (darwinStatsProvider) Capabilities() StatsCapabilities {
+	return StatsCapabilities{CPU: true, Memory: true, Network: true, IRQ: false}
+}
+
+func (p darwinStatsProvider) CollectSystemStats() (*SystemStats, error) {
+	stats := &SystemStats{}
+
+	cpuUtil, err := p.cpuUtilization()
+	if err != nil {
+		return nil, fmt.Errorf("cpu utilization: %w", err)
+	}
+	stats.CPUUtilization = cpuUtil
+
+	memUsage, err := p.memoryUsage()
+	if err != nil {
+		return nil, fmt.Errorf("memory usage: %w", err)
+	}
+	stats.MemoryUsage = memUsage
+
+	netStats, err := p.networkStats()
+	if err != nil {
+		return nil, fmt.Errorf("network stats: %w", err)
+	}
+	stats.NetworkStats = netStats
+
+	// IRQ counts aren't exposed on macOS the way they are via
+	// /proc/interrupts; leave IRQRate at zero (see Capabilities).
+	return stats, nil
+}
+
+// cpuUtilization parses the "CPU usage: X% user, Y% sys, Z% idle" summary
+// line `top -l 1` prints.
+func (p darwinStatsProvider) cpuUtilization() (float64, error) {
+	out, err := exec.Command("top", "-l", "1", "-n", "0", "-stats", "cpu").Output()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.HasPrefix(line, "CPU usage:") {
+			continue
+		}
+		// "CPU usage: 12.34% user, 5.67% sys, 82.0% idle"
+		var idle float64
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if strings.HasSuffix(part, "idle") {
+				fields := strings.Fields(part)
+				if len(fields) > 0 {
+					idle, _ = strconv.ParseFloat(strings.TrimSuffix(fields[0], "%"), 64)
+				}
+			}
+		}
+		return 100 - idle, nil
+	}
+
+	return 0, nil
+}
+
+// memoryUsage combines `sysctl hw.memsize` (total bytes) with the free and
+// speculative page counts from `vm_stat` to approximate used memory percent.
+func (p darwinStatsProvider) memoryUsage() (float64, error) {
+	totalOut, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0, err
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(string(totalOut)), 10, 64)
+	if err != nil || total == 0 {
+		return 0, err
+	}
+
+	vmOut, err := exec.Command("vm_stat").Output()
+	if err != nil {
+		return 0, err
+	}
+
+	pageSize := int64(4096)
+	pages := map[string]int64{}
+	for _, line := range strings.Split(string(vmOut), "\n") {
+		if strings.HasPrefix(line, "Mach Virtual Memory Statistics") {
+			// "... (page size of 4096 bytes)"
+			if idx := strings.Index(line, "page size of "); idx >= 0 {
+				fields := strings.Fields(line[idx+len("page size of "):])
+				if len(fields) > 0 {
+					if sz, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+						pageSize = sz
+					}
+				}
+			}
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(parts[1]), "."))
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			pages[name] = n
+		}
+	}
+
+	freePages := pages["Pages free"] + pages["Pages speculative"]
+	available := freePages * pageSize
+	used := total - available
+	return float64(used) / float64(total) * 100, nil
+}
+
+// networkStats parses `netstat -ib`, which prints one row per NIC with
+// cumulative packet/byte counters since boot.
+func (p darwinStatsProvider) networkStats() (*NetworkStats, error) {
+	out, err := exec.Command("netstat", "-ib").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &NetworkStats{}
+	lines := strings.Split(string(out), "\n")
+	if len(lines) == 0 {
+		return stats, nil
+	}
+
+	header := strings.Fields(lines[0])
+	col := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		return -1
+	}
+	nameCol, ibytesCol, obytesCol := col("Name"), col("Ibytes"), col("Obytes")
+	ipktsCol, opktsCol := col("Ipkts"), col("Opkts")
+	if nameCol < 0 || ibytesCol < 0 || obytesCol < 0 {
+		return stats, nil
+	}
+
+	seen := map[string]bool{}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) <= ibytesCol || len(fields) <= obytesCol {
+			continue
+		}
+		name := fields[nameCol]
+		// netstat -ib lists one row per address family per NIC; keep the
+		// first (link-layer) row for each interface name.
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		nic := NICStats{Name: name}
+		nic.BytesReceived, _ = strconv.ParseInt(fields[ibytesCol], 10, 64)
+		nic.BytesSent, _ = strconv.ParseInt(fields[obytesCol], 10, 64)
+		if ipktsCol >= 0 && len(fields) > ipktsCol {
+			nic.PacketsReceived, _ = strconv.ParseInt(fields[ipktsCol], 10, 64)
+		}
+		if opktsCol >= 0 && len(fields) > opktsCol {
+			nic.PacketsSent, _ = strconv.ParseInt(fields[opktsCol], 10, 64)
+		}
+		stats.Interfaces = append(stats.Interfaces, nic)
+
+		if strings.HasPrefix(name, "en0") {
+			stats.BytesReceived = nic.BytesReceived
+			stats.PacketsReceived = nic.PacketsReceived
+			stats.BytesSent = nic.BytesSent
+			stats.PacketsSent = nic.PacketsSent
+		}
+	}
+
+	return stats, nil
+}
@@ -1,18 +1,31 @@
 package instances
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
-	"bufio"
+
+	"r2-bench/instances/imds"
 )
 
 // EC2Monitor handles EC2 instance monitoring and system statistics
 type EC2Monitor struct {
 	instanceType string
 	lastStats    *SystemStats
+	provider     SystemStatsProvider
+
+	// metadata is nil on a non-EC2 host, where imds.Client.Fetch fails
+	// fast instead of returning data. GetRegion/GetAZ/GetENIInfo report
+	// zero values in that case.
+	metadata *imds.InstanceMetadata
+
+	// cgroupMonitor is nil when no cgroup hierarchy is found (e.g. a
+	// non-Linux host). GetSystemStats leaves SystemStats.ContainerStats
+	// nil then, and also whenever cgroupMonitor.Sample fails (e.g. outside
+	// a container, where the root cgroup lacks a file Sample reads).
+	cgroupMonitor *CgroupMonitor
 }
 
 // SystemStats holds system-level statistics
@@ -22,193 +35,98 @@ type SystemStats struct {
 	MemoryUsage    float64
 	NetworkStats   *NetworkStats
 	Timestamp      time.Time
+
+	// Capabilities reports which of the fields above this host's
+	// SystemStatsProvider could actually populate; see StatsCapabilities.
+	Capabilities StatsCapabilities
+
+	// ContainerStats is nil unless EC2Monitor found a readable cgroup, in
+	// which case it holds container-relative CPU/memory/network usage
+	// alongside the host-relative fields above; see CgroupMonitor.
+	ContainerStats *ContainerStats
 }
 
 // NetworkStats holds network interface statistics
 type NetworkStats struct {
-	BytesReceived    int64
-	BytesSent        int64
-	PacketsReceived  int64
-	PacketsSent      int64
-	Retransmits      int64
-	LinkUtilPct      float64
+	BytesReceived   int64
+	BytesSent       int64
+	PacketsReceived int64
+	PacketsSent     int64
+	Retransmits     int64
+	LinkUtilPct     float64
+
+	// Interfaces holds per-NIC counters the provider could enumerate;
+	// the fields above mirror whichever one the provider picked as
+	// primary, for callers that only track one NIC. Whether those
+	// counters are cumulative since-boot totals or an already-computed
+	// rate depends on RatesPerSecond.
+	Interfaces []NICStats
+
+	// RatesPerSecond is true when BytesReceived/BytesSent/
+	// PacketsReceived/PacketsSent (here and on every NICStats above) are
+	// already bytes/packets *per second*, computed by the provider from
+	// a delta over the sampling interval (linux_sampler.go, windows_pdh.go).
+	// It is false when they are raw cumulative counters since boot
+	// (darwin_sysctl.go, bsd_sysctl.go, solaris_kstat.go), which a caller
+	// wanting a rate must diff against a previous sample itself.
+	RatesPerSecond bool
 }
 
-// NewEC2Monitor creates a new EC2 monitor
-func NewEC2Monitor() (*EC2Monitor, error) {
-	instanceType := getInstanceType()
-	
-	return &EC2Monitor{
-		instanceType: instanceType,
-		lastStats:    &SystemStats{},
-	}, nil
+// NICStats holds one network interface's counters; see
+// NetworkStats.RatesPerSecond for whether they are cumulative or a rate.
+type NICStats struct {
+	Name            string
+	BytesReceived   int64
+	BytesSent       int64
+	PacketsReceived int64
+	PacketsSent     int64
 }
 
-// GetSystemStats collects current system statistics
-func (em *EC2Monitor) GetSystemStats() (*SystemStats, error) {
-	stats := &SystemStats{
-		Timestamp: time.Now(),
-	}
-
-	// Get CPU utilization
-	cpuUtil, err := em.getCPUUtilization()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get CPU utilization: %w", err)
-	}
-	stats.CPUUtilization = cpuUtil
-
-	// Get IRQ rate
-	irqRate, err := em.getIRQRate()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get IRQ rate: %w", err)
+// NewEC2Monitor creates a new EC2 monitor. It tries IMDSv2 first for
+// instance type, region, AZ, and ENI info; on a non-EC2 host that fails
+// fast (imds.Client has a 1s dial timeout), and instanceType falls back
+// to the /sys/hypervisor/uuid heuristic or the EC2_INSTANCE_TYPE env var.
+func NewEC2Monitor() (*EC2Monitor, error) {
+	em := &EC2Monitor{
+		instanceType: getInstanceType(),
+		lastStats:    &SystemStats{},
+		provider:     newSystemStatsProvider(),
 	}
-	stats.IRQRate = irqRate
 
-	// Get memory usage
-	memUsage, err := em.getMemoryUsage()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get memory usage: %w", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if metadata, err := imds.NewClient().Fetch(ctx); err == nil {
+		em.metadata = metadata
+		em.instanceType = metadata.InstanceType
 	}
-	stats.MemoryUsage = memUsage
 
-	// Get network statistics
-	netStats, err := em.getNetworkStats()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get network stats: %w", err)
+	if monitor, err := NewCgroupMonitor(); err == nil {
+		em.cgroupMonitor = monitor
 	}
-	stats.NetworkStats = netStats
-
-	em.lastStats = stats
-	return stats, nil
-}
 
-// getCPUUtilization reads CPU utilization from /proc/stat
-func (em *EC2Monitor) getCPUUtilization() (float64, error) {
-	file, err := os.Open("/proc/stat")
-	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	if scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Fields(line)
-		if len(fields) >= 5 && fields[0] == "cpu" {
-			// Parse CPU times
-			user, _ := strconv.ParseInt(fields[1], 10, 64)
-			nice, _ := strconv.ParseInt(fields[2], 10, 64)
-			system, _ := strconv.ParseInt(fields[3], 10, 64)
-			idle, _ := strconv.ParseInt(fields[4], 10, 64)
-			
-			total := user + nice + system + idle
-			used := user + nice + system
-			
-			if total > 0 {
-				return float64(used) / float64(total) * 100, nil
-			}
-		}
-	}
-	
-	return 0, nil
+	return em, nil
 }
 
-// getIRQRate reads IRQ rate from /proc/interrupts
-func (em *EC2Monitor) getIRQRate() (float64, error) {
-	file, err := os.Open("/proc/interrupts")
+// GetSystemStats collects current system statistics by delegating to the
+// platform's SystemStatsProvider (see stats_provider.go).
+func (em *EC2Monitor) GetSystemStats() (*SystemStats, error) {
+	stats, err := em.provider.CollectSystemStats()
 	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	totalIRQs := 0
-	lineCount := 0
-	
-	for scanner.Scan() && lineCount < 10 {
-		line := scanner.Text()
-		fields := strings.Fields(line)
-		if len(fields) > 0 {
-			// Count IRQs (first field is usually the IRQ number)
-			if _, err := strconv.Atoi(fields[0]); err == nil {
-				totalIRQs++
-			}
-		}
-		lineCount++
+		return nil, fmt.Errorf("failed to collect system stats: %w", err)
 	}
-	
-	// This is a simplified approach - in practice you'd want to track IRQ changes over time
-	return float64(totalIRQs), nil
-}
+	stats.Timestamp = time.Now()
+	stats.Capabilities = em.provider.Capabilities()
 
-// getMemoryUsage reads memory usage from /proc/meminfo
-func (em *EC2Monitor) getMemoryUsage() (float64, error) {
-	file, err := os.Open("/proc/meminfo")
-	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var total, available int64
-	
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "MemTotal:") {
-			fields := strings.Fields(line)
-			if len(fields) >= 2 {
-				total, _ = strconv.ParseInt(fields[1], 10, 64)
-			}
-		} else if strings.HasPrefix(line, "MemAvailable:") {
-			fields := strings.Fields(line)
-			if len(fields) >= 2 {
-				available, _ = strconv.ParseInt(fields[1], 10, 64)
-			}
+	// A transient read failure here (e.g. a cgroup file disappearing
+	// across a container restart) just leaves ContainerStats nil rather
+	// than failing the whole host-relative GetSystemStats call.
+	if em.cgroupMonitor != nil {
+		if containerStats, err := em.cgroupMonitor.Sample(); err == nil {
+			stats.ContainerStats = containerStats
 		}
 	}
-	
-	if total > 0 {
-		used := total - available
-		return float64(used) / float64(total) * 100, nil
-	}
-	
-	return 0, nil
-}
 
-// getNetworkStats reads network statistics from /proc/net/dev
-func (em *EC2Monitor) getNetworkStats() (*NetworkStats, error) {
-	file, err := os.Open("/proc/net/dev")
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	stats := &NetworkStats{}
-	
-	// Skip header lines
-	scanner.Scan()
-	scanner.Scan()
-	
-	for scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Fields(line)
-		if len(fields) >= 17 {
-			// Look for the primary network interface (usually eth0 or ens5)
-			if strings.Contains(fields[0], "eth0") || strings.Contains(fields[0], "ens5") {
-				stats.BytesReceived, _ = strconv.ParseInt(fields[1], 10, 64)
-				stats.PacketsReceived, _ = strconv.ParseInt(fields[2], 10, 64)
-				stats.BytesSent, _ = strconv.ParseInt(fields[9], 10, 64)
-				stats.PacketsSent, _ = strconv.ParseInt(fields[10], 10, 64)
-				break
-			}
-		}
-	}
-	
-	// Calculate link utilization (simplified)
-	// In practice, you'd want to track this over time to get actual utilization
-	stats.LinkUtilPct = 0.0
-	
+	em.lastStats = stats
 	return stats, nil
 }
 
@@ -223,12 +141,12 @@ func getInstanceType() string {
 			return "ec2-instance"
 		}
 	}
-	
+
 	// Fallback to environment variable or default
 	if instanceType := os.Getenv("EC2_INSTANCE_TYPE"); instanceType != "" {
 		return instanceType
 	}
-	
+
 	return "unknown"
 }
 
@@ -236,3 +154,28 @@ func getInstanceType() string {
 func (em *EC2Monitor) GetInstanceType() string {
 	return em.instanceType
 }
+
+// GetRegion returns the instance's AWS region, or "" on a non-EC2 host.
+func (em *EC2Monitor) GetRegion() string {
+	if em.metadata == nil {
+		return ""
+	}
+	return em.metadata.Region
+}
+
+// GetAZ returns the instance's availability zone, or "" on a non-EC2 host.
+func (em *EC2Monitor) GetAZ() string {
+	if em.metadata == nil {
+		return ""
+	}
+	return em.metadata.AZ
+}
+
+// GetENIInfo returns the instance's attached network interfaces, or nil
+// on a non-EC2 host.
+func (em *EC2Monitor) GetENIInfo() []imds.ENIInfo {
+	if em.metadata == nil {
+		return nil
+	}
+	return em.metadata.ENIs
+}
@@ -0,0 +1,87 @@
+// Package export publishes instances.SystemStats samples to external
+// monitoring systems. It replaces the one-shot GetSystemStats usage
+// pattern (call it once, do something with the result) with a Monitor
+// that samples on an interval and fans each sample out to every
+// registered Sink - a Prometheus collector, an OTLP/gRPC exporter, or
+// whatever else implements the one-method Sink interface.
+package export
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"r2-bench/instances"
+)
+
+// Sink publishes one SystemStats sample to an external monitoring
+// system. Implementations must tolerate being called repeatedly from a
+// single Monitor.Run loop, but need not be safe for concurrent use by
+// more than one loop.
+type Sink interface {
+	Export(ctx context.Context, stats *instances.SystemStats) error
+}
+
+// cumulativeDeltas turns a series of cumulative since-boot counter
+// readings into per-tick deltas, for sinks that accumulate a platform's
+// raw counters (see instances.NetworkStats.RatesPerSecond) into their own
+// Counter instead of re-reporting the cumulative value itself. A Sink is
+// only ever driven by one Monitor.Run loop (see the Sink doc comment), so
+// this needs no locking.
+type cumulativeDeltas struct {
+	last map[string]int64
+}
+
+// next returns cur's delta against the previous reading stored under key,
+// and false on the first reading for key (there is nothing to diff yet)
+// or if cur rolled back below the previous reading (the counter wrapped
+// or the provider restarted; the delta is discarded rather than guessed).
+func (d *cumulativeDeltas) next(key string, cur int64) (int64, bool) {
+	if d.last == nil {
+		d.last = make(map[string]int64)
+	}
+	prev, ok := d.last[key]
+	d.last[key] = cur
+	if !ok || cur < prev {
+		return 0, false
+	}
+	return cur - prev, true
+}
+
+// Monitor samples an EC2Monitor on an interval and fans each SystemStats
+// sample out to every registered Sink.
+type Monitor struct {
+	source *instances.EC2Monitor
+}
+
+// NewMonitor builds a Monitor that samples source.
+func NewMonitor(source *instances.EC2Monitor) *Monitor {
+	return &Monitor{source: source}
+}
+
+// Run samples source.GetSystemStats every interval and exports each
+// sample to every sink, until ctx is cancelled. A sink returning an error
+// is logged and skipped for that tick rather than stopping the loop or
+// blocking the other sinks' exports.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration, sinks ...Sink) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			stats, err := m.source.GetSystemStats()
+			if err != nil {
+				slog.Default().Error("export: collect system stats", "err", err)
+				continue
+			}
+			for _, sink := range sinks {
+				if err := sink.Export(ctx, stats); err != nil {
+					slog.Default().Error("export: sink failed", "err", err)
+				}
+			}
+		}
+	}
+}
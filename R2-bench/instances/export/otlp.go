@@ -0,0 +1,146 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"r2-bench/instances"
+)
+
+// OTLPSink batches SystemStats samples into OpenTelemetry metric points
+// and exports them over OTLP/gRPC, tagging every point with the
+// instance.type, cloud.region, and cloud.availability_zone resource
+// attributes EC2Monitor resolved from IMDS.
+type OTLPSink struct {
+	provider *sdkmetric.MeterProvider
+
+	cpuUtilization metric.Float64Gauge
+	memoryUsage    metric.Float64Gauge
+	irqRate        metric.Float64Gauge
+
+	// netBytesRate/netPacketsRate record already-computed bytes/sec and
+	// packets/sec on platforms where NetworkStats.RatesPerSecond is true;
+	// netBytesTotal/netPacketsTotal/netRetransmitsTotal accumulate a true
+	// per-tick delta on platforms reporting cumulative since-boot
+	// counters instead. See PrometheusSink for why only one set is used.
+	netBytesRate   metric.Float64Gauge
+	netPacketsRate metric.Float64Gauge
+
+	netBytesTotal       metric.Float64Counter
+	netPacketsTotal     metric.Float64Counter
+	netRetransmitsTotal metric.Float64Counter
+
+	deltas cumulativeDeltas
+}
+
+// NewOTLPSink dials endpoint (e.g. "localhost:4317") over OTLP/gRPC and
+// builds a MeterProvider whose Resource identifies this host using
+// mon's already-resolved instance type, region, and availability zone -
+// the same fields GetRegion/GetAZ/GetInstanceType expose.
+func NewOTLPSink(ctx context.Context, endpoint string, mon *instances.EC2Monitor) (*OTLPSink, error) {
+	exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("export: dial otlp endpoint %s: %w", endpoint, err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		attribute.String("instance.type", mon.GetInstanceType()),
+		attribute.String("cloud.region", mon.GetRegion()),
+		attribute.String("cloud.availability_zone", mon.GetAZ()),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("export: build otlp resource: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+	)
+	meter := provider.Meter("r2-bench/instances/export")
+
+	sink := &OTLPSink{provider: provider}
+	if sink.cpuUtilization, err = meter.Float64Gauge("tumidp_cpu_utilization_ratio"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_cpu_utilization_ratio: %w", err)
+	}
+	if sink.memoryUsage, err = meter.Float64Gauge("tumidp_memory_usage_ratio"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_memory_usage_ratio: %w", err)
+	}
+	if sink.irqRate, err = meter.Float64Gauge("tumidp_irq_rate"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_irq_rate: %w", err)
+	}
+	if sink.netBytesRate, err = meter.Float64Gauge("tumidp_net_bytes_per_second"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_net_bytes_per_second: %w", err)
+	}
+	if sink.netPacketsRate, err = meter.Float64Gauge("tumidp_net_packets_per_second"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_net_packets_per_second: %w", err)
+	}
+	if sink.netBytesTotal, err = meter.Float64Counter("tumidp_net_bytes_total"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_net_bytes_total: %w", err)
+	}
+	if sink.netPacketsTotal, err = meter.Float64Counter("tumidp_net_packets_total"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_net_packets_total: %w", err)
+	}
+	if sink.netRetransmitsTotal, err = meter.Float64Counter("tumidp_net_retransmits_total"); err != nil {
+		return nil, fmt.Errorf("export: create instrument tumidp_net_retransmits_total: %w", err)
+	}
+
+	return sink, nil
+}
+
+// Export records one SystemStats sample as OTLP metric points. The
+// underlying MeterProvider batches and ships them on its own periodic
+// schedule rather than on every Export call.
+func (s *OTLPSink) Export(ctx context.Context, stats *instances.SystemStats) error {
+	s.cpuUtilization.Record(ctx, stats.CPUUtilization/100)
+	s.memoryUsage.Record(ctx, stats.MemoryUsage/100)
+	s.irqRate.Record(ctx, stats.IRQRate)
+
+	if stats.NetworkStats == nil {
+		return nil
+	}
+	net := stats.NetworkStats
+
+	if net.RatesPerSecond {
+		for _, nic := range net.Interfaces {
+			iface := attribute.String("iface", nic.Name)
+			s.netBytesRate.Record(ctx, float64(nic.BytesReceived), metric.WithAttributes(iface, attribute.String("direction", "rx")))
+			s.netBytesRate.Record(ctx, float64(nic.BytesSent), metric.WithAttributes(iface, attribute.String("direction", "tx")))
+			s.netPacketsRate.Record(ctx, float64(nic.PacketsReceived), metric.WithAttributes(iface, attribute.String("direction", "rx")))
+			s.netPacketsRate.Record(ctx, float64(nic.PacketsSent), metric.WithAttributes(iface, attribute.String("direction", "tx")))
+		}
+		return nil
+	}
+
+	for _, nic := range net.Interfaces {
+		iface := attribute.String("iface", nic.Name)
+		if d, ok := s.deltas.next(nic.Name+":rx_bytes", nic.BytesReceived); ok {
+			s.netBytesTotal.Add(ctx, float64(d), metric.WithAttributes(iface, attribute.String("direction", "rx")))
+		}
+		if d, ok := s.deltas.next(nic.Name+":tx_bytes", nic.BytesSent); ok {
+			s.netBytesTotal.Add(ctx, float64(d), metric.WithAttributes(iface, attribute.String("direction", "tx")))
+		}
+		if d, ok := s.deltas.next(nic.Name+":rx_packets", nic.PacketsReceived); ok {
+			s.netPacketsTotal.Add(ctx, float64(d), metric.WithAttributes(iface, attribute.String("direction", "rx")))
+		}
+		if d, ok := s.deltas.next(nic.Name+":tx_packets", nic.PacketsSent); ok {
+			s.netPacketsTotal.Add(ctx, float64(d), metric.WithAttributes(iface, attribute.String("direction", "tx")))
+		}
+	}
+	if d, ok := s.deltas.next("retransmits", net.Retransmits); ok {
+		s.netRetransmitsTotal.Add(ctx, float64(d), metric.WithAttributes(attribute.String("iface", "primary")))
+	}
+
+	return nil
+}
+
+// Shutdown flushes any buffered metric points and closes the underlying
+// OTLP/gRPC connection.
+func (s *OTLPSink) Shutdown(ctx context.Context) error {
+	return s.provider.Shutdown(ctx)
+}
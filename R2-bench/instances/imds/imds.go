@@ -0,0 +1,191 @@
+// Package imds implements a minimal EC2 Instance Metadata Service v2
+// (IMDSv2) client: token-based session auth plus lookups for instance
+// type, placement, and network interfaces. Nitro-based instances (nearly
+// all current EC2 hardware) don't populate /sys/hypervisor/uuid the way
+// older Xen instances did, so that file is no longer a reliable way to
+// even detect we're running on EC2, let alone which instance type.
+package imds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	baseURL        = "http://169.254.169.254/latest"
+	tokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+	tokenHeader    = "X-aws-ec2-metadata-token"
+	tokenTTL       = "21600" // 6 hours, IMDSv2's own default session length
+
+	dialTimeout = 1 * time.Second // non-EC2 hosts (laptops, most CI) must fail fast, not hang on a link-local address
+	maxRetries  = 3
+)
+
+// Client talks to the instance metadata service using the IMDSv2
+// token-based protocol.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient builds an IMDSv2 client with a short per-request timeout.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: dialTimeout},
+		baseURL:    baseURL,
+	}
+}
+
+// InstanceMetadata is the subset of IMDS Fetch resolves.
+type InstanceMetadata struct {
+	InstanceType string
+	Region       string
+	AZ           string
+	ENIs         []ENIInfo
+}
+
+// ENIInfo identifies one network interface as reported under
+// /latest/meta-data/network/interfaces/macs/<mac>/.
+type ENIInfo struct {
+	MAC          string
+	InterfaceID  string
+	DeviceNumber string
+}
+
+// Fetch resolves instance type, region, availability zone, and attached
+// ENIs in one call. Each call re-authenticates with a fresh token rather
+// than caching one across the Client's lifetime; callers that want to
+// avoid repeated round-trips should cache the *InstanceMetadata
+// themselves, as EC2Monitor does.
+func (c *Client) Fetch(ctx context.Context) (*InstanceMetadata, error) {
+	instanceType, err := c.get(ctx, "/meta-data/instance-type")
+	if err != nil {
+		return nil, err
+	}
+	az, err := c.get(ctx, "/meta-data/placement/availability-zone")
+	if err != nil {
+		return nil, err
+	}
+	region, err := c.get(ctx, "/meta-data/placement/region")
+	if err != nil {
+		return nil, err
+	}
+
+	macs, err := c.get(ctx, "/meta-data/network/interfaces/macs/")
+	if err != nil {
+		return nil, err
+	}
+
+	var enis []ENIInfo
+	for _, mac := range strings.Split(strings.TrimSpace(macs), "\n") {
+		mac = strings.TrimSuffix(strings.TrimSpace(mac), "/")
+		if mac == "" {
+			continue
+		}
+		ifaceID, err := c.get(ctx, fmt.Sprintf("/meta-data/network/interfaces/macs/%s/interface-id", mac))
+		if err != nil {
+			continue // not every ENI exposes an interface-id (e.g. classic-networking instances)
+		}
+		deviceNumber, err := c.get(ctx, fmt.Sprintf("/meta-data/network/interfaces/macs/%s/device-number", mac))
+		if err != nil {
+			continue
+		}
+		enis = append(enis, ENIInfo{MAC: mac, InterfaceID: ifaceID, DeviceNumber: deviceNumber})
+	}
+
+	return &InstanceMetadata{InstanceType: instanceType, Region: region, AZ: az, ENIs: enis}, nil
+}
+
+// token fetches a fresh IMDSv2 session token; every metadata GET needs one.
+func (c *Client) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(tokenTTLHeader, tokenTTL)
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return "", fmt.Errorf("imds: fetch token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("imds: read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("imds: fetch token: unexpected status %d", resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// get issues a token-authenticated GET against path (e.g.
+// "/meta-data/instance-type"), relative to baseURL.
+func (c *Client) get(ctx context.Context, path string) (string, error) {
+	tok, err := c.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(tokenHeader, tok)
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return "", fmt.Errorf("imds: get %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("imds: read %s response: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("imds: get %s: unexpected status %d", path, resp.StatusCode)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// doWithRetry sends req, retrying with exponential backoff (100ms, 200ms,
+// 400ms) on a 5xx response - IMDS occasionally returns one under load
+// right after an instance boots, before it has finished initializing. A
+// transport-level error (connection refused, timeout) is not retried: on a
+// non-EC2 host that's the expected outcome for every attempt, and retrying
+// it would turn dialTimeout's fail-fast guarantee into several seconds of
+// backoff per metadata field. Backoff sleeps respect req's context so a
+// caller's deadline still bounds the whole call, not just the dial.
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+			backoff *= 2
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package instances
+
+import "time"
+
+// defaultSamplerInterval seeds the Sampler newPlatformStatsProvider builds;
+// EC2Monitor.GetSystemStats doesn't currently take a polling interval of
+// its own, so this is just the elapsed-time fallback Sampler.Sample uses
+// if it's ever called faster than real wall-clock time moves.
+const defaultSamplerInterval = time.Second
+
+// linuxStatsProvider implements SystemStatsProvider by delegating to a
+// Sampler, which turns the cumulative counters in /proc/stat, /proc/
+// interrupts, and /proc/net/dev into rates (see linux_sampler.go).
+type linuxStatsProvider struct {
+	sampler *Sampler
+}
+
+func newPlatformStatsProvider() SystemStatsProvider {
+	return &linuxStatsProvider{sampler: NewSampler(defaultSamplerInterval)}
+}
+
+func (*linuxStatsProvider) Capabilities() StatsCapabilities {
+	return StatsCapabilities{CPU: true, Memory: true, Network: true, IRQ: true}
+}
+
+func (p *linuxStatsProvider) CollectSystemStats() (*SystemStats, error) {
+	return p.sampler.Sample()
+}
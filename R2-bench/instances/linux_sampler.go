@@ -0,0 +1,373 @@
+//go:build linux
+
+package instances
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sampler turns the raw, monotonically-increasing counters /proc exposes
+// into per-interval rates. A single snapshot of /proc/stat, /proc/
+// interrupts, or /proc/net/dev is a cumulative total since boot, not a
+// rate - reading CPU utilization, IRQ rate, or link utilization off one
+// snapshot (as the original getCPUUtilization/getIRQRate/getNetworkStats
+// did) only happens to work because those counters start at zero.
+// Sampler keeps the previous snapshot and derives
+// (current - previous) / elapsed on every Sample call.
+type Sampler struct {
+	interval time.Duration
+
+	mu          sync.Mutex
+	prev        *procSnapshot
+	prevAt      time.Time
+	prevNICRate map[string]nicDelta // last computed delta, reused across a wrapped counter
+}
+
+// NewSampler builds a Sampler. interval is the caller's expected polling
+// cadence; it's only used as the elapsed-time fallback for a Sample()
+// called so quickly after the previous one that the real elapsed time
+// underflows to zero. The very first Sample() computes no rates at all.
+func NewSampler(interval time.Duration) *Sampler {
+	return &Sampler{interval: interval, prevNICRate: map[string]nicDelta{}}
+}
+
+// Sample reads the current /proc counters and returns a SystemStats whose
+// CPUUtilization, IRQRate, and NetworkStats are rates computed against the
+// previous Sample call. The first call after construction has no previous
+// snapshot to diff against, so those fields come back zero; MemoryUsage is
+// always a real instantaneous reading since it isn't a counter.
+func (s *Sampler) Sample() (*SystemStats, error) {
+	now := time.Now()
+	snap, err := captureProcSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	memUsage, err := readMemoryUsage()
+	if err != nil {
+		return nil, fmt.Errorf("memory usage: %w", err)
+	}
+
+	stats := &SystemStats{
+		Timestamp:    now,
+		MemoryUsage:  memUsage,
+		NetworkStats: &NetworkStats{},
+	}
+
+	s.mu.Lock()
+	prev, prevAt := s.prev, s.prevAt
+	s.prev, s.prevAt = snap, now
+	s.mu.Unlock()
+
+	if prev == nil {
+		return stats, nil
+	}
+
+	elapsed := now.Sub(prevAt).Seconds()
+	if elapsed <= 0 {
+		elapsed = s.interval.Seconds()
+	}
+	if elapsed <= 0 {
+		elapsed = 1
+	}
+
+	stats.CPUUtilization = cpuUtilFromDelta(prev.cpu, snap.cpu)
+	stats.IRQRate = irqRateFromDelta(prev.irqTotal, snap.irqTotal, elapsed)
+	stats.NetworkStats = s.networkStatsFromDelta(prev.nics, snap.nics, elapsed)
+
+	return stats, nil
+}
+
+// cpuJiffies is the subset of /proc/stat's cumulative CPU tick counters
+// the standard utilization formula needs.
+type cpuJiffies struct {
+	user, nice, system, idle, iowait, irq, softirq, steal int64
+}
+
+// nicCounters is one NIC's cumulative /proc/net/dev counters.
+type nicCounters struct {
+	rxBytes, txBytes     int64
+	rxPackets, txPackets int64
+	rxErrors, rxDropped  int64
+	txErrors, txDropped  int64
+}
+
+// nicDelta is the per-interval change in a NIC's byte/packet counters,
+// cached so a wrapped 32-bit counter can reuse the last known-good delta
+// instead of producing a huge negative rate.
+type nicDelta struct {
+	rxBytes, txBytes, rxPackets, txPackets int64
+}
+
+type procSnapshot struct {
+	cpu      cpuJiffies
+	irqTotal int64 // sum, across every IRQ line and every CPU column, of /proc/interrupts
+	nics     map[string]nicCounters
+}
+
+func captureProcSnapshot() (*procSnapshot, error) {
+	cpu, err := readCPUJiffies()
+	if err != nil {
+		return nil, fmt.Errorf("read /proc/stat: %w", err)
+	}
+	irqTotal, err := readIRQTotal()
+	if err != nil {
+		return nil, fmt.Errorf("read /proc/interrupts: %w", err)
+	}
+	nics, err := readNICCounters()
+	if err != nil {
+		return nil, fmt.Errorf("read /proc/net/dev: %w", err)
+	}
+	return &procSnapshot{cpu: cpu, irqTotal: irqTotal, nics: nics}, nil
+}
+
+// readCPUJiffies parses the aggregate "cpu" line of /proc/stat.
+func readCPUJiffies() (cpuJiffies, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuJiffies{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return cpuJiffies{}, nil
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 9 || fields[0] != "cpu" {
+		return cpuJiffies{}, nil
+	}
+
+	parse := func(i int) int64 {
+		v, _ := strconv.ParseInt(fields[i], 10, 64)
+		return v
+	}
+	return cpuJiffies{
+		user:    parse(1),
+		nice:    parse(2),
+		system:  parse(3),
+		idle:    parse(4),
+		iowait:  parse(5),
+		irq:     parse(6),
+		softirq: parse(7),
+		steal:   parse(8),
+	}, nil
+}
+
+// cpuUtilFromDelta applies the standard jiffies-delta formula:
+// 1 - (idle_delta + iowait_delta) / total_delta.
+func cpuUtilFromDelta(prev, cur cpuJiffies) float64 {
+	prevTotal := prev.user + prev.nice + prev.system + prev.idle + prev.iowait + prev.irq + prev.softirq + prev.steal
+	curTotal := cur.user + cur.nice + cur.system + cur.idle + cur.iowait + cur.irq + cur.softirq + cur.steal
+
+	totalDelta := curTotal - prevTotal
+	if totalDelta <= 0 {
+		return 0
+	}
+	idleDelta := (cur.idle - prev.idle) + (cur.iowait - prev.iowait)
+	return (1 - float64(idleDelta)/float64(totalDelta)) * 100
+}
+
+// readIRQTotal sums every IRQ line's per-CPU counters in /proc/interrupts,
+// using the CPU-label header only to count CPU columns and skipping each
+// line's leading "<irq>:" label and trailing descriptive text (the
+// interrupt controller/device name).
+func readIRQTotal() (int64, error) {
+	file, err := os.Open("/proc/interrupts")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	numCPUs := 0
+	if scanner.Scan() {
+		numCPUs = len(strings.Fields(scanner.Text()))
+	}
+
+	var total int64
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		// fields[0] is "<irq>:"; the next numCPUs fields are per-CPU
+		// counts, followed by the controller type and device name.
+		end := 1 + numCPUs
+		if end > len(fields) {
+			end = len(fields)
+		}
+		for _, f := range fields[1:end] {
+			if v, err := strconv.ParseInt(f, 10, 64); err == nil {
+				total += v
+			}
+		}
+	}
+	return total, nil
+}
+
+// irqRateFromDelta converts a total-IRQ-count delta into a rate,
+// reusing 0 rather than going negative if the (64-bit, so effectively
+// non-wrapping) running total ever looks like it went backwards, e.g.
+// after a counter reset.
+func irqRateFromDelta(prev, cur int64, elapsed float64) float64 {
+	delta := cur - prev
+	if delta < 0 {
+		delta = 0
+	}
+	return float64(delta) / elapsed
+}
+
+// readNICCounters parses every interface line of /proc/net/dev.
+func readNICCounters() (map[string]nicCounters, error) {
+	file, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // inter-|   receive ...
+	scanner.Scan() // face  |bytes packets errs ...
+
+	nics := map[string]nicCounters{}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 17 {
+			continue
+		}
+		name := strings.TrimSuffix(fields[0], ":")
+		parse := func(i int) int64 {
+			v, _ := strconv.ParseInt(fields[i], 10, 64)
+			return v
+		}
+		nics[name] = nicCounters{
+			rxBytes:   parse(1),
+			rxErrors:  parse(3),
+			rxDropped: parse(4),
+			txBytes:   parse(9),
+			txErrors:  parse(11),
+			txDropped: parse(12),
+			// /proc/net/dev's packet columns sit between the byte and
+			// error columns.
+			rxPackets: parse(2),
+			txPackets: parse(10),
+		}
+	}
+	return nics, nil
+}
+
+// networkStatsFromDelta converts every NIC's counter delta into
+// bytes/packets per second, plus a LinkUtilPct for the interface chosen as
+// primary, and handles 32-bit counter wraparound by reusing the last
+// known-good delta for a NIC whose current reading is less than its
+// previous one.
+func (s *Sampler) networkStatsFromDelta(prev, cur map[string]nicCounters, elapsed float64) *NetworkStats {
+	stats := &NetworkStats{RatesPerSecond: true}
+
+	for name, c := range cur {
+		p, ok := prev[name]
+		if !ok {
+			continue
+		}
+
+		last := s.prevNICRate[name]
+		d := nicDelta{
+			rxBytes:   counterDelta(p.rxBytes, c.rxBytes, last.rxBytes),
+			txBytes:   counterDelta(p.txBytes, c.txBytes, last.txBytes),
+			rxPackets: counterDelta(p.rxPackets, c.rxPackets, last.rxPackets),
+			txPackets: counterDelta(p.txPackets, c.txPackets, last.txPackets),
+		}
+		s.prevNICRate[name] = d
+
+		nic := NICStats{
+			Name:            name,
+			BytesReceived:   int64(float64(d.rxBytes) / elapsed),
+			BytesSent:       int64(float64(d.txBytes) / elapsed),
+			PacketsReceived: int64(float64(d.rxPackets) / elapsed),
+			PacketsSent:     int64(float64(d.txPackets) / elapsed),
+		}
+		stats.Interfaces = append(stats.Interfaces, nic)
+
+		if strings.Contains(name, "eth0") || strings.Contains(name, "ens5") {
+			stats.BytesReceived = nic.BytesReceived
+			stats.PacketsReceived = nic.PacketsReceived
+			stats.BytesSent = nic.BytesSent
+			stats.PacketsSent = nic.PacketsSent
+			stats.LinkUtilPct = linkUtilPct(name, d.rxBytes, d.txBytes, elapsed)
+		}
+	}
+
+	return stats
+}
+
+// counterDelta computes cur-prev, treating cur < prev as a wrapped 32-bit
+// counter (we can't tell how many times it wrapped) and reusing prevDelta,
+// the delta observed in the prior interval, instead.
+func counterDelta(prev, cur, prevDelta int64) int64 {
+	if cur < prev {
+		return prevDelta
+	}
+	return cur - prev
+}
+
+// linkUtilPct computes (rxBytesDelta+txBytesDelta)*8 / (linkSpeedBps *
+// elapsed), reading the NIC's negotiated speed from
+// /sys/class/net/<iface>/speed (megabits/sec). Returns 0 if the interface
+// has no speed file or reports a speed that isn't meaningful (virtual
+// interfaces report -1).
+func linkUtilPct(iface string, rxBytesDelta, txBytesDelta int64, elapsed float64) float64 {
+	data, err := os.ReadFile(fmt.Sprintf("/sys/class/net/%s/speed", iface))
+	if err != nil {
+		return 0
+	}
+	speedMbps, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || speedMbps <= 0 {
+		return 0
+	}
+
+	linkSpeedBps := float64(speedMbps) * 1_000_000
+	bitsTransferred := float64(rxBytesDelta+txBytesDelta) * 8
+	return bitsTransferred / (linkSpeedBps * elapsed) * 100
+}
+
+// readMemoryUsage reads memory usage from /proc/meminfo. Unlike CPU, IRQ,
+// and NIC counters, memory usage is an instantaneous gauge, not a
+// cumulative counter, so it needs no delta against a previous sample.
+func readMemoryUsage() (float64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var total, available int64
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "MemTotal:") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				total, _ = strconv.ParseInt(fields[1], 10, 64)
+			}
+		} else if strings.HasPrefix(line, "MemAvailable:") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				available, _ = strconv.ParseInt(fields[1], 10, 64)
+			}
+		}
+	}
+
+	if total > 0 {
+		used := total - available
+		return float64(used) / float64(total) * 100, nil
+	}
+	return 0, nil
+}
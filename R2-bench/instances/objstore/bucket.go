@@ -0,0 +1,46 @@
+// Package objstore is a driver-registry abstraction over object-store
+// backends, modeled after Thanos' objstore and Arvados' keepstore driver
+// map: a single Bucket interface plus named driver factories registered
+// via init(), so BenchmarkRunner loads a backend from a config blob
+// (see Config/New) instead of the CLI sniffing a URL for "r2" and hardcoding
+// the rest.
+package objstore
+
+import "context"
+
+// Bucket is the minimal interface every storage backend driver must
+// implement to be benchmarked: range reads, whole-object upload,
+// existence/size checks, and multipart upload for large writes.
+type Bucket interface {
+	// GetObjectRange returns [start, start+length) of objectKey's bytes.
+	GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
+	// UploadObject writes data as objectKey in a single request.
+	UploadObject(ctx context.Context, objectKey string, data []byte) error
+	// ObjectExists reports whether objectKey exists in the bucket.
+	ObjectExists(ctx context.Context, objectKey string) (bool, error)
+	// GetObjectSize returns objectKey's size in bytes.
+	GetObjectSize(ctx context.Context, objectKey string) (int64, error)
+	// GetEndpoint returns the URL this Bucket issues requests against, for
+	// display and as the `endpoint` metric/Parquet label.
+	GetEndpoint() string
+
+	MultipartUploader
+}
+
+// CompletedPart identifies one uploaded part for CompleteMultipartUpload,
+// mirroring S3's own CompletedPart shape.
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// MultipartUploader is the part of Bucket covering large-object uploads
+// done in pieces instead of one UploadObject call. Each driver maps this
+// onto whatever its provider calls the same idea: S3/R2/MinIO's multipart
+// upload API, Azure's block-blob staging, or GCS's object composition.
+type MultipartUploader interface {
+	CreateMultipartUpload(ctx context.Context, objectKey string) (uploadID string, err error)
+	UploadPart(ctx context.Context, objectKey, uploadID string, partNumber int, data []byte) (etag string, err error)
+	CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error
+}
@@ -0,0 +1,187 @@
+package objstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// gcsMaxComposeSources is GCS's limit on source objects per Compose call;
+// CompleteMultipartUpload folds a longer part list into a running compose
+// instead of one call.
+const gcsMaxComposeSources = 32
+
+// gcsBucket implements Bucket against Google Cloud Storage.
+type gcsBucket struct {
+	bucket     *storage.BucketHandle
+	bucketName string
+}
+
+// gcsSettings is the "settings" block for the "gcs" driver.
+type gcsSettings struct {
+	// CredentialsFile is a service-account JSON key file path; empty uses
+	// Application Default Credentials.
+	CredentialsFile string `yaml:"credentials_file"`
+}
+
+// NewGCSBucket builds a Bucket against Google Cloud Storage.
+func NewGCSBucket(ctx context.Context, bucketName, credentialsFile string) (Bucket, error) {
+	var opts []option.ClientOption
+	if credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credentialsFile))
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: create GCS client: %w", err)
+	}
+	return &gcsBucket{bucket: client.Bucket(bucketName), bucketName: bucketName}, nil
+}
+
+func init() {
+	Register("gcs", func(cfg Config) (Bucket, error) {
+		var settings gcsSettings
+		if err := cfg.decodeSettings(&settings); err != nil {
+			return nil, err
+		}
+		return NewGCSBucket(context.Background(), cfg.Bucket, settings.CredentialsFile)
+	})
+}
+
+func (b *gcsBucket) GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error) {
+	r, err := b.bucket.Object(objectKey).NewRangeReader(ctx, start, length)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: get object range: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: read object body: %w", err)
+	}
+	return data, nil
+}
+
+func (b *gcsBucket) UploadObject(ctx context.Context, objectKey string, data []byte) error {
+	w := b.bucket.Object(objectKey).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("objstore: upload object: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("objstore: finalize upload: %w", err)
+	}
+	return nil
+}
+
+func (b *gcsBucket) ObjectExists(ctx context.Context, objectKey string) (bool, error) {
+	_, err := b.bucket.Object(objectKey).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("objstore: check object existence: %w", err)
+	}
+	return true, nil
+}
+
+func (b *gcsBucket) GetObjectSize(ctx context.Context, objectKey string) (int64, error) {
+	attrs, err := b.bucket.Object(objectKey).Attrs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("objstore: get object size: %w", err)
+	}
+	return attrs.Size, nil
+}
+
+func (b *gcsBucket) GetEndpoint() string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s", b.bucketName)
+}
+
+// GCS has no native multipart upload API, so CreateMultipartUpload just
+// allocates an ID namespacing this upload's temporary per-part objects;
+// CompleteMultipartUpload folds them into the final object via Compose
+// (https://cloud.google.com/storage/docs/composing-objects) and
+// AbortMultipartUpload deletes them.
+func (b *gcsBucket) CreateMultipartUpload(ctx context.Context, objectKey string) (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("objstore: generate upload id: %w", err)
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+func (b *gcsBucket) partObjectKey(objectKey, uploadID string, partNumber int) string {
+	return fmt.Sprintf(".multipart/%s/%s/%d", objectKey, uploadID, partNumber)
+}
+
+func (b *gcsBucket) UploadPart(ctx context.Context, objectKey, uploadID string, partNumber int, data []byte) (string, error) {
+	key := b.partObjectKey(objectKey, uploadID, partNumber)
+	if err := b.UploadObject(ctx, key, data); err != nil {
+		return "", fmt.Errorf("objstore: upload part %d: %w", partNumber, err)
+	}
+	return key, nil
+}
+
+func (b *gcsBucket) CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []CompletedPart) error {
+	srcs := make([]*storage.ObjectHandle, len(parts))
+	for i, p := range parts {
+		srcs[i] = b.bucket.Object(b.partObjectKey(objectKey, uploadID, p.PartNumber))
+	}
+
+	dst := b.bucket.Object(objectKey)
+	for len(srcs) > 0 {
+		n := len(srcs)
+		if n > gcsMaxComposeSources {
+			n = gcsMaxComposeSources
+		}
+		if _, err := dst.ComposerFrom(srcs[:n]...).Run(ctx); err != nil {
+			return fmt.Errorf("objstore: compose object: %w", err)
+		}
+		srcs = srcs[n:]
+		if len(srcs) > 0 {
+			// Fold the running result back in as the next compose's first
+			// source so an arbitrary number of parts converges to one object.
+			srcs = append([]*storage.ObjectHandle{dst}, srcs...)
+		}
+	}
+
+	prefix := fmt.Sprintf(".multipart/%s/%s/", objectKey, uploadID)
+	it := b.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("objstore: list staged parts: %w", err)
+		}
+		if err := b.bucket.Object(attrs.Name).Delete(ctx); err != nil {
+			return fmt.Errorf("objstore: delete staged part %s: %w", attrs.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b *gcsBucket) AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error {
+	prefix := fmt.Sprintf(".multipart/%s/%s/", objectKey, uploadID)
+	it := b.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("objstore: list staged parts: %w", err)
+		}
+		if err := b.bucket.Object(attrs.Name).Delete(ctx); err != nil {
+			return fmt.Errorf("objstore: delete staged part %s: %w", attrs.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package objstore
+
+import "fmt"
+
+// minioSettings is the "settings" block for the "minio" driver: MinIO
+// speaks the S3 API but is usually self-hosted behind a plain HTTP(S)
+// endpoint with path-style addressing, hence its own driver rather than
+// asking users to hand-configure "s3" with the right overrides.
+type minioSettings struct {
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+}
+
+// NewMinioBucket builds a Bucket against a MinIO (or other path-style,
+// self-hosted S3-compatible) server.
+func NewMinioBucket(endpoint, region, accessKeyID, secretAccessKey, bucket string) (Bucket, error) {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return newS3CompatBucket(s3CompatConfig{
+		Bucket:          bucket,
+		Region:          region,
+		Endpoint:        endpoint,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		UsePathStyle:    true,
+	})
+}
+
+func init() {
+	Register("minio", func(cfg Config) (Bucket, error) {
+		var settings minioSettings
+		if err := cfg.decodeSettings(&settings); err != nil {
+			return nil, err
+		}
+		if settings.Endpoint == "" || settings.AccessKeyID == "" || settings.SecretAccessKey == "" {
+			return nil, fmt.Errorf("objstore: minio driver requires endpoint, access_key_id, and secret_access_key settings")
+		}
+		return NewMinioBucket(settings.Endpoint, settings.Region, settings.AccessKeyID, settings.SecretAccessKey, cfg.Bucket)
+	})
+}
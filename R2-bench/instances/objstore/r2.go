@@ -0,0 +1,34 @@
+package objstore
+
+import "fmt"
+
+// r2Settings is the "settings" block for the "r2" driver.
+type r2Settings struct {
+	AccountID       string `yaml:"account_id"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+}
+
+// NewR2Bucket builds a Bucket against Cloudflare R2's S3-compatible API.
+func NewR2Bucket(accountID, accessKeyID, secretAccessKey, bucket string) (Bucket, error) {
+	return newS3CompatBucket(s3CompatConfig{
+		Bucket:          bucket,
+		Region:          "auto", // R2 uses "auto" region
+		Endpoint:        fmt.Sprintf("https://%s.r2.cloudflarestorage.com", accountID),
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	})
+}
+
+func init() {
+	Register("r2", func(cfg Config) (Bucket, error) {
+		var settings r2Settings
+		if err := cfg.decodeSettings(&settings); err != nil {
+			return nil, err
+		}
+		if settings.AccountID == "" || settings.AccessKeyID == "" || settings.SecretAccessKey == "" {
+			return nil, fmt.Errorf("objstore: r2 driver requires account_id, access_key_id, and secret_access_key settings")
+		}
+		return NewR2Bucket(settings.AccountID, settings.AccessKeyID, settings.SecretAccessKey, cfg.Bucket)
+	})
+}
@@ -0,0 +1,102 @@
+package objstore
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Factory builds a Bucket from an endpoint's Config, decoding the driver's
+// own settings out of it (see Config.decodeSettings). Each driver
+// registers one via Register in its own init() (see s3.go, r2.go, minio.go,
+// gcs.go, azure.go, filesystem.go).
+type Factory func(cfg Config) (Bucket, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a named driver factory. Panics on a duplicate name: that
+// can only be a packaging mistake (two drivers claiming the same name),
+// never a runtime condition a caller could recover from.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("objstore: driver %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New builds a Bucket using the driver named by cfg.Driver.
+func New(cfg Config) (Bucket, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Driver]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("objstore: unknown driver %q (known drivers: %s)", cfg.Driver, strings.Join(knownDrivers(), ", "))
+	}
+	return factory(cfg)
+}
+
+func knownDrivers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config is the driver-agnostic configuration blob for one endpoint,
+// typically loaded from YAML (a valid JSON document parses the same way,
+// since JSON is a subset of YAML):
+//
+//	driver: s3
+//	bucket: my-bucket
+//	settings:
+//	  region: us-east-1
+//
+// Settings is re-decoded into each driver's own settings struct inside its
+// factory (e.g. s3Settings in s3.go).
+type Config struct {
+	Driver   string    `yaml:"driver"`
+	Bucket   string    `yaml:"bucket"`
+	Settings yaml.Node `yaml:"settings"`
+}
+
+// decodeSettings decodes cfg.Settings into out, leaving out at its zero
+// value when the config didn't include a "settings" block at all.
+func (cfg Config) decodeSettings(out interface{}) error {
+	if cfg.Settings.IsZero() {
+		return nil
+	}
+	if err := cfg.Settings.Decode(out); err != nil {
+		return fmt.Errorf("objstore: decode %q driver settings: %w", cfg.Driver, err)
+	}
+	return nil
+}
+
+// LoadConfigFile reads and parses a single endpoint's driver config from a
+// YAML or JSON file.
+func LoadConfigFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("objstore: read %s: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("objstore: parse %s: %w", path, err)
+	}
+	if cfg.Driver == "" {
+		return Config{}, fmt.Errorf("objstore: %s: missing required \"driver\" field", path)
+	}
+	return cfg, nil
+}
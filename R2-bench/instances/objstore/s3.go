@@ -0,0 +1,390 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/logging"
+)
+
+// slogAWSLogger adapts a *slog.Logger to the AWS SDK's logging.Logger
+// interface, so the SDK's own retry/signing/request messages - gated by
+// ClientLogMode on the client that owns this logger - flow through the
+// same structured sink as everything else in the process.
+type slogAWSLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogAWSLogger) Logf(classification logging.Classification, format string, v ...interface{}) {
+	level := slog.LevelInfo
+	if classification == logging.Warn {
+		level = slog.LevelWarn
+	}
+	l.logger.Log(context.Background(), level, fmt.Sprintf(format, v...), "source", "aws-sdk")
+}
+
+// s3Bucket is the shared implementation behind the "s3", "r2", and "minio"
+// drivers: all three speak the S3 API and differ only in endpoint, region,
+// credentials, and path-style addressing, which s3CompatConfig captures.
+type s3Bucket struct {
+	client      *s3.Client
+	bucketName  string
+	endpoint    string
+	credentials aws.CredentialsProvider
+
+	// authMu guards authToken/authExpiration, which mirror Arvados'
+	// AuthToken/AuthExpiration client fields: the access key ID and expiry
+	// of the credentials most recently resolved from credentials, so a
+	// caller can tell whether a run is on temporary (instance-profile,
+	// assumed-role, or web-identity) credentials and when they next
+	// rotate, without reaching into the AWS SDK's own types.
+	authMu         sync.RWMutex
+	authToken      string
+	authExpiration time.Time
+}
+
+// s3CompatConfig configures an s3Bucket for any S3-compatible provider.
+type s3CompatConfig struct {
+	Bucket          string
+	Region          string // "auto" for R2
+	Endpoint        string // empty uses AWS's default resolver
+	AccessKeyID     string // empty uses the default credential chain
+	SecretAccessKey string
+	UsePathStyle    bool // MinIO and most self-hosted S3-compatible servers need this
+
+	// AssumeRoleARN, when set, is assumed via sts:AssumeRole on top of
+	// whatever base credentials resolve first (static keys above, or the
+	// default chain's environment/shared-config/instance-profile
+	// resolution), so a benchmark running as one IAM identity can still
+	// target a bucket that trusts a different role.
+	AssumeRoleARN         string
+	AssumeRoleExternalID  string
+	AssumeRoleSessionName string
+	AssumeRoleMFASerial   string // when set, prompts for the MFA code on stdin
+
+	// WebIdentityTokenFile and WebIdentityRoleARN mirror IRSA's
+	// AWS_WEB_IDENTITY_TOKEN_FILE/AWS_ROLE_ARN env vars explicitly, for
+	// an EKS pod whose projected service-account token isn't wired into
+	// those env vars by the pod spec.
+	WebIdentityTokenFile string
+	WebIdentityRoleARN   string
+
+	// LogRetries and LogRequests, when set, route the AWS SDK's own
+	// retry/signing/request logging through slog.Default() via
+	// slogAWSLogger, so the retries and mid-stream errors the SDK's
+	// middleware stack sees end up in the same structured sink as
+	// everything else instead of nowhere. They're process-wide once
+	// enabled (the SDK's Logger is bound at client construction, not
+	// per-call), so they can't carry a specific request's trace fields -
+	// pair with timing and object_key to correlate by hand.
+	LogRetries  bool
+	LogRequests bool
+}
+
+// newS3CompatBucket builds an s3Bucket from cfg; shared by the s3, r2, and
+// minio drivers.
+func newS3CompatBucket(cfg s3CompatConfig) (*s3Bucket, error) {
+	ctx := context.Background()
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(cfg.Region),
+	}
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")))
+	}
+	if cfg.Endpoint != "" {
+		endpoint := cfg.Endpoint
+		resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: endpoint}, nil
+		})
+		opts = append(opts, config.WithEndpointResolverWithOptions(resolver))
+	}
+	if cfg.LogRetries || cfg.LogRequests {
+		var logMode aws.ClientLogMode
+		if cfg.LogRetries {
+			logMode |= aws.LogRetries
+		}
+		if cfg.LogRequests {
+			logMode |= aws.LogRequestWithBody | aws.LogResponseWithBody
+		}
+		opts = append(opts, config.WithLogger(slogAWSLogger{slog.Default()}), config.WithClientLogMode(logMode))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: load AWS config: %w", err)
+	}
+
+	// The chain config.LoadDefaultConfig just built already resolves EC2
+	// instance-profile credentials via IMDS (ec2rolecreds) and IRSA
+	// web-identity tokens from the standard env vars on its own, with
+	// automatic refresh before expiry baked into the SDK's own
+	// credential cache. The two blocks below layer on what that chain
+	// can't do unprompted: hop through an explicit assume-role, and use
+	// an explicit token file/role pair when the IRSA env vars aren't set.
+	if cfg.WebIdentityTokenFile != "" {
+		stsClient := sts.NewFromConfig(awsCfg)
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(
+			stsClient, cfg.WebIdentityRoleARN, stscreds.IdentityTokenFile(cfg.WebIdentityTokenFile)))
+	}
+	if cfg.AssumeRoleARN != "" {
+		stsClient := sts.NewFromConfig(awsCfg)
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, cfg.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if cfg.AssumeRoleExternalID != "" {
+				o.ExternalID = aws.String(cfg.AssumeRoleExternalID)
+			}
+			if cfg.AssumeRoleSessionName != "" {
+				o.RoleSessionName = cfg.AssumeRoleSessionName
+			}
+			if cfg.AssumeRoleMFASerial != "" {
+				o.SerialNumber = aws.String(cfg.AssumeRoleMFASerial)
+				o.TokenProvider = stscreds.StdinTokenProvider
+			}
+		}))
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+
+	bucket := &s3Bucket{client: client, bucketName: cfg.Bucket, endpoint: endpoint, credentials: awsCfg.Credentials}
+	if err := bucket.refreshAuthStatus(ctx); err != nil {
+		return nil, fmt.Errorf("objstore: resolve initial credentials: %w", err)
+	}
+	return bucket, nil
+}
+
+// refreshAuthStatus retrieves the current credentials from b.credentials
+// and caches their access key ID and expiration for AuthToken/
+// AuthExpiration. The SDK's CredentialsCache refreshes the underlying
+// credentials on its own whenever a request needs signing; this just
+// surfaces what it last resolved to.
+func (b *s3Bucket) refreshAuthStatus(ctx context.Context) error {
+	creds, err := b.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("objstore: retrieve credentials: %w", err)
+	}
+	b.authMu.Lock()
+	b.authToken = creds.AccessKeyID
+	if creds.CanExpire {
+		b.authExpiration = creds.Expires
+	} else {
+		b.authExpiration = time.Time{}
+	}
+	b.authMu.Unlock()
+	return nil
+}
+
+// AuthToken returns the access key ID of the credentials this bucket is
+// currently using, re-resolving from the underlying provider first so a
+// rotated instance-profile or assumed-role credential is reflected
+// immediately rather than after the next request.
+func (b *s3Bucket) AuthToken(ctx context.Context) (string, error) {
+	if err := b.refreshAuthStatus(ctx); err != nil {
+		return "", err
+	}
+	b.authMu.RLock()
+	defer b.authMu.RUnlock()
+	return b.authToken, nil
+}
+
+// AuthExpiration returns when the current credentials expire, or the zero
+// Time for credentials that don't expire (e.g. static access keys).
+func (b *s3Bucket) AuthExpiration(ctx context.Context) (time.Time, error) {
+	if err := b.refreshAuthStatus(ctx); err != nil {
+		return time.Time{}, err
+	}
+	b.authMu.RLock()
+	defer b.authMu.RUnlock()
+	return b.authExpiration, nil
+}
+
+func (b *s3Bucket) GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error) {
+	rangeHeader := fmt.Sprintf("bytes=%d-%d", start, start+length-1)
+
+	result, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucketName),
+		Key:    aws.String(objectKey),
+		Range:  aws.String(rangeHeader),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("objstore: get object range: %w", err)
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: read object body: %w", err)
+	}
+	return body, nil
+}
+
+func (b *s3Bucket) UploadObject(ctx context.Context, objectKey string, data []byte) error {
+	_, err := b.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.bucketName),
+		Key:    aws.String(objectKey),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("objstore: upload object: %w", err)
+	}
+	return nil
+}
+
+func (b *s3Bucket) ObjectExists(ctx context.Context, objectKey string) (bool, error) {
+	_, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		var notFoundErr *types.NoSuchKey
+		if errors.As(err, &notFoundErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("objstore: check object existence: %w", err)
+	}
+	return true, nil
+}
+
+func (b *s3Bucket) GetObjectSize(ctx context.Context, objectKey string) (int64, error) {
+	result, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("objstore: get object size: %w", err)
+	}
+	return result.ContentLength, nil
+}
+
+func (b *s3Bucket) GetEndpoint() string {
+	return b.endpoint
+}
+
+func (b *s3Bucket) CreateMultipartUpload(ctx context.Context, objectKey string) (string, error) {
+	out, err := b.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(b.bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return "", fmt.Errorf("objstore: create multipart upload: %w", err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+func (b *s3Bucket) UploadPart(ctx context.Context, objectKey, uploadID string, partNumber int, data []byte) (string, error) {
+	out, err := b.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(b.bucketName),
+		Key:        aws.String(objectKey),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(int32(partNumber)),
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("objstore: upload part %d: %w", partNumber, err)
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func (b *s3Bucket) CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []CompletedPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = types.CompletedPart{
+			ETag:       aws.String(p.ETag),
+			PartNumber: aws.Int32(int32(p.PartNumber)),
+		}
+	}
+	_, err := b.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(b.bucketName),
+		Key:             aws.String(objectKey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("objstore: complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (b *s3Bucket) AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error {
+	_, err := b.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(b.bucketName),
+		Key:      aws.String(objectKey),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("objstore: abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// s3Settings is the "settings" block for the "s3" driver. Region is the
+// only thing most users set; the rest only matter for assuming a role,
+// using an explicit web-identity token file instead of the default
+// credential chain's own EC2 instance-profile/IRSA resolution, or turning
+// on the AWS SDK's own retry/request logging.
+type s3Settings struct {
+	Region string `yaml:"region"`
+
+	AssumeRoleARN         string `yaml:"assume_role_arn"`
+	AssumeRoleExternalID  string `yaml:"assume_role_external_id"`
+	AssumeRoleSessionName string `yaml:"assume_role_session_name"`
+	AssumeRoleMFASerial   string `yaml:"assume_role_mfa_serial"`
+
+	WebIdentityTokenFile string `yaml:"web_identity_token_file"`
+	WebIdentityRoleARN   string `yaml:"web_identity_role_arn"`
+
+	LogRetries  bool `yaml:"log_retries"`
+	LogRequests bool `yaml:"log_requests"`
+}
+
+// NewS3Bucket builds a Bucket using the AWS default credential chain
+// (static keys aren't an option here; use the "s3" driver's Config for
+// anything beyond that default chain, including assume-role and
+// web-identity).
+func NewS3Bucket(region, bucket string) (Bucket, error) {
+	return newS3CompatBucket(s3CompatConfig{Bucket: bucket, Region: region})
+}
+
+func init() {
+	Register("s3", func(cfg Config) (Bucket, error) {
+		var settings s3Settings
+		if err := cfg.decodeSettings(&settings); err != nil {
+			return nil, err
+		}
+		if settings.Region == "" {
+			settings.Region = "us-east-1"
+		}
+		return newS3CompatBucket(s3CompatConfig{
+			Bucket:                cfg.Bucket,
+			Region:                settings.Region,
+			AssumeRoleARN:         settings.AssumeRoleARN,
+			AssumeRoleExternalID:  settings.AssumeRoleExternalID,
+			AssumeRoleSessionName: settings.AssumeRoleSessionName,
+			AssumeRoleMFASerial:   settings.AssumeRoleMFASerial,
+			WebIdentityTokenFile:  settings.WebIdentityTokenFile,
+			WebIdentityRoleARN:    settings.WebIdentityRoleARN,
+			LogRetries:            settings.LogRetries,
+			LogRequests:           settings.LogRequests,
+		})
+	})
+}
@@ -0,0 +1,56 @@
+// Package s3test spins up an in-process, in-memory S3-compatible HTTP
+// server backed by johannesboyne/gofakes3 and its s3mem backend, mirroring
+// Arvados' own switch to gofakes3 for exercising its S3 keepstore without a
+// real bucket. It lets the worker/collector pipeline, the download
+// manager, and the Parquet writer be driven end-to-end in tests without
+// AWS credentials or a network call.
+package s3test
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+
+	"r2-bench/instances/objstore"
+)
+
+// Server owns the in-process fake S3 HTTP server behind Bucket. Callers
+// must Close it once done to release the listener.
+type Server struct {
+	httpServer *httptest.Server
+
+	// Bucket is an objstore.Bucket pointed at the fake server, ready to
+	// pass anywhere a real driver's Bucket would go.
+	Bucket objstore.Bucket
+}
+
+// NewServer starts the fake S3 server with an empty bucket named
+// bucketName already created, and returns a Server wrapping an
+// objstore.Bucket client for it. It talks to the fake server through the
+// "minio" driver's path-style construction, since gofakes3 only
+// understands path-style requests.
+func NewServer(bucketName string) (*Server, error) {
+	backend := s3mem.New()
+	faker := gofakes3.New(backend)
+	httpServer := httptest.NewServer(faker.Server())
+
+	if err := backend.CreateBucket(bucketName); err != nil {
+		httpServer.Close()
+		return nil, fmt.Errorf("s3test: create bucket %q: %w", bucketName, err)
+	}
+
+	bucket, err := objstore.NewMinioBucket(httpServer.URL, "us-east-1", "s3test-access-key", "s3test-secret-key", bucketName)
+	if err != nil {
+		httpServer.Close()
+		return nil, fmt.Errorf("s3test: build bucket client: %w", err)
+	}
+
+	return &Server{httpServer: httpServer, Bucket: bucket}, nil
+}
+
+// Close shuts down the underlying HTTP server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
@@ -0,0 +1,108 @@
+package s3test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"r2-bench/pkg/download"
+	"r2-bench/pkg/results"
+)
+
+// TestServerRangeReadAndParquetPipeline drives the fake S3 backend through
+// the same path a real benchmark run takes: upload an object, fetch it
+// back in concurrent parts via the download manager, and record the part
+// results through the Parquet writer, confirming they round-trip.
+func TestServerRangeReadAndParquetPipeline(t *testing.T) {
+	srv, err := NewServer("bench-bucket")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	ctx := context.Background()
+	objectKey := "objects/range-read-test"
+	want := make([]byte, 3*download.DefaultPartSize+1234)
+	for i := range want {
+		want[i] = byte(i % 251)
+	}
+
+	if err := srv.Bucket.UploadObject(ctx, objectKey, want); err != nil {
+		t.Fatalf("UploadObject: %v", err)
+	}
+
+	size, err := srv.Bucket.GetObjectSize(ctx, objectKey)
+	if err != nil {
+		t.Fatalf("GetObjectSize: %v", err)
+	}
+	if size != int64(len(want)) {
+		t.Fatalf("GetObjectSize = %d, want %d", size, len(want))
+	}
+
+	mgr := download.NewManager(0, 0)
+	got := make(download.BufferWriterAt, len(want))
+	parts := mgr.Download(ctx, srv.Bucket, objectKey, 0, int64(len(want)), got)
+
+	if len(got) != len(want) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("downloaded content mismatch at byte %d", i)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "s3test-results")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writer, err := results.NewParquetWriter(results.WriterConfig{
+		BaseDir:      dir,
+		RunID:        "test-run",
+		InstanceType: "test-instance",
+	})
+	if err != nil {
+		t.Fatalf("NewParquetWriter: %v", err)
+	}
+
+	records := make(chan results.Record, len(parts))
+	for _, p := range parts {
+		if p.Err != nil {
+			t.Fatalf("part %d: %v", p.PartNumber, p.Err)
+		}
+		records <- results.Record{
+			ObjectKey:  objectKey,
+			RangeStart: p.RangeStart,
+			RangeLen:   p.RangeLen,
+			PartNumber: p.PartNumber,
+			Bytes:      p.Bytes,
+			RunID:      "test-run",
+		}
+	}
+	close(records)
+
+	if err := writer.Ingest(ctx, records); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+
+	path := writer.CurrentPath()
+	reader, err := results.OpenReader(path)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	defer reader.Close()
+
+	var gotBytes int64
+	for i := 0; i < len(parts); i++ {
+		rec, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		gotBytes += rec.Bytes
+	}
+	if gotBytes != int64(len(want)) {
+		t.Fatalf("sum of recorded part bytes = %d, want %d", gotBytes, len(want))
+	}
+}
@@ -0,0 +1,212 @@
+//go:build solaris
+
+package instances
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// solarisStatsProvider implements SystemStatsProvider via kstat(1M), the
+// same mechanism gopsutil's Solaris support (see its #1381 patch) uses for
+// disk and network counters, since Solaris has neither /proc/net/dev nor a
+// BSD-style sysctl(8) network MIB.
+type solarisStatsProvider struct{}
+
+func newPlatformStatsProvider() SystemStatsProvider {
+	return solarisStatsProvider{}
+}
+
+func (solarisStatsProvider) Capabilities() StatsCapabilities {
+	return StatsCapabilities{CPU: true, Memory: true, Network: true, IRQ: false}
+}
+
+func (p solarisStatsProvider) CollectSystemStats() (*SystemStats, error) {
+	stats := &SystemStats{}
+
+	cpuUtil, err := p.cpuUtilization()
+	if err != nil {
+		return nil, fmt.Errorf("cpu utilization: %w", err)
+	}
+	stats.CPUUtilization = cpuUtil
+
+	memUsage, err := p.memoryUsage()
+	if err != nil {
+		return nil, fmt.Errorf("memory usage: %w", err)
+	}
+	stats.MemoryUsage = memUsage
+
+	netStats, err := p.networkStats()
+	if err != nil {
+		return nil, fmt.Errorf("network stats: %w", err)
+	}
+	stats.NetworkStats = netStats
+
+	// Solaris doesn't expose a per-source IRQ count the way Linux does;
+	// leave IRQRate at zero (see Capabilities).
+	return stats, nil
+}
+
+// kstatFields runs `kstat -p -c class` and returns each line split on ':',
+// the "module:instance:name:statistic value" format -p emits.
+func kstatFields(class string) ([][]string, error) {
+	out, err := exec.Command("kstat", "-p", "-c", class).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		rows = append(rows, strings.SplitN(line, ":", 4))
+	}
+	return rows, nil
+}
+
+// cpuUtilization sums the cpu_stat kstat's per-state tick counters
+// (idle/user/kernel/wait) across all CPUs.
+func (p solarisStatsProvider) cpuUtilization() (float64, error) {
+	rows, err := kstatFields("misc")
+	if err != nil {
+		return 0, err
+	}
+
+	var idle, user, kernel, wait float64
+	for _, row := range rows {
+		if len(row) != 4 || row[0] != "cpu_stat" {
+			continue
+		}
+		// row[3] is "statistic\tvalue"
+		parts := strings.Fields(row[3])
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			continue
+		}
+		switch parts[0] {
+		case "idle":
+			idle += value
+		case "user":
+			user += value
+		case "kernel":
+			kernel += value
+		case "wait":
+			wait += value
+		}
+	}
+
+	total := idle + user + kernel + wait
+	if total == 0 {
+		return 0, nil
+	}
+	return (user + kernel + wait) / total * 100, nil
+}
+
+// memoryUsage reads total/free pages from the unix:0:system_pages kstat.
+func (p solarisStatsProvider) memoryUsage() (float64, error) {
+	rows, err := kstatFields("pages")
+	if err != nil {
+		return 0, err
+	}
+
+	var totalPages, freePages float64
+	for _, row := range rows {
+		if len(row) != 4 || row[2] != "system_pages" {
+			continue
+		}
+		parts := strings.Fields(row[3])
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			continue
+		}
+		switch parts[0] {
+		case "pagestotal":
+			totalPages = value
+		case "pagesfree":
+			freePages = value
+		}
+	}
+
+	if totalPages == 0 {
+		return 0, nil
+	}
+	used := totalPages - freePages
+	return used / totalPages * 100, nil
+}
+
+// networkStats parses `kstat -p -c net`'s per-NIC rbytes/obytes/ipackets/
+// opackets counters, as the gopsutil #1381 patch does for net.IOCounters.
+func (p solarisStatsProvider) networkStats() (*NetworkStats, error) {
+	rows, err := kstatFields("net")
+	if err != nil {
+		return nil, err
+	}
+
+	type counters struct {
+		rbytes, obytes, ipackets, opackets float64
+	}
+	byNIC := map[string]*counters{}
+	order := []string{}
+
+	for _, row := range rows {
+		if len(row) != 4 {
+			continue
+		}
+		name := row[2]
+		parts := strings.Fields(row[3])
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			continue
+		}
+		c, ok := byNIC[name]
+		if !ok {
+			c = &counters{}
+			byNIC[name] = c
+			order = append(order, name)
+		}
+		switch parts[0] {
+		case "rbytes", "rbytes64":
+			c.rbytes = value
+		case "obytes", "obytes64":
+			c.obytes = value
+		case "ipackets", "ipackets64":
+			c.ipackets = value
+		case "opackets", "opackets64":
+			c.opackets = value
+		}
+	}
+
+	stats := &NetworkStats{}
+	for _, name := range order {
+		c := byNIC[name]
+		nic := NICStats{
+			Name:            name,
+			BytesReceived:   int64(c.rbytes),
+			BytesSent:       int64(c.obytes),
+			PacketsReceived: int64(c.ipackets),
+			PacketsSent:     int64(c.opackets),
+		}
+		stats.Interfaces = append(stats.Interfaces, nic)
+	}
+	if len(stats.Interfaces) > 0 {
+		primary := stats.Interfaces[0]
+		stats.BytesReceived = primary.BytesReceived
+		stats.PacketsReceived = primary.PacketsReceived
+		stats.BytesSent = primary.BytesSent
+		stats.PacketsSent = primary.PacketsSent
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,36 @@
+package instances
+
+// SystemStatsProvider samples CPU, memory, network, and (where available)
+// IRQ counters for the host EC2Monitor is running on. It is implemented
+// once per OS family - linux_proc.go, darwin_sysctl.go, windows_pdh.go,
+// bsd_sysctl.go, solaris_kstat.go - selected at build time via Go build
+// tags, the same way gopsutil splits its cpu/mem/net/disk packages per
+// platform instead of one file assuming /proc exists everywhere.
+type SystemStatsProvider interface {
+	// CollectSystemStats samples the current CPU utilization, memory
+	// usage, network counters, and IRQ rate. Fields the provider can't
+	// populate on this OS are left at their zero value; Capabilities
+	// tells the caller which zeroes are real.
+	CollectSystemStats() (*SystemStats, error)
+
+	// Capabilities reports which SystemStats fields this provider can
+	// actually populate on the current OS.
+	Capabilities() StatsCapabilities
+}
+
+// StatsCapabilities flags which SystemStats fields a SystemStatsProvider
+// can populate, so a caller comparing runs across instance types/OSes can
+// tell a genuine zero from a field that OS simply doesn't expose.
+type StatsCapabilities struct {
+	CPU     bool
+	Memory  bool
+	Network bool
+	IRQ     bool
+}
+
+// newSystemStatsProvider is implemented per platform (one file per OS
+// family, gated by a build tag) and returns the provider for the OS this
+// binary was built for.
+func newSystemStatsProvider() SystemStatsProvider {
+	return newPlatformStatsProvider()
+}
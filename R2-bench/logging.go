@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	logFormat        = flag.String("log-format", "json", "Structured log output format: json or text")
+	logLevelF        = flag.String("log-level", "info", "Minimum log level: debug, info, warn, error")
+	logOutput        = flag.String("log-output", "stdout", "Log output destination: stdout, stderr, or a file path")
+	logSampling      = flag.Int("log-sampling", 1, "Log 1 in N successful requests at debug level (1 = log every request)")
+	logDedupInterval = flag.Duration("log-dedup-interval", 30*time.Second, "Collapse repeated identical log lines (same level and message) into a periodic count summary instead of emitting each one; 0 disables deduplication")
+)
+
+// newLogger builds the process-wide structured logger from -log-format,
+// -log-level, and -log-output. JSON is the default so output ships straight
+// into the same pipeline as everything else; -log-format=text is for
+// reading a run live in a terminal. Repeated identical log lines (e.g. a
+// "results channel full" warning firing on every dropped result during a
+// backlog) are collapsed by dedupHandler rather than flooding whichever
+// sink -log-output points at.
+func newLogger() (*slog.Logger, error) {
+	w, err := openLogOutput(*logOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: parseLogLevel(*logLevelF)}
+
+	var handler slog.Handler
+	if *logFormat == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	if *logDedupInterval > 0 {
+		handler = newDedupHandler(handler, *logDedupInterval)
+	}
+	return slog.New(handler), nil
+}
+
+// openLogOutput resolves -log-output to a writer: the two standard streams
+// by name, or a file path opened for append.
+func openLogOutput(dest string) (*os.File, error) {
+	switch dest {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("log output: open %s: %w", dest, err)
+		}
+		return f, nil
+	}
+}
+
+func parseLogLevel(s string) slog.Level {
+	switch s {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// shouldSampleRequestLog reports whether the request that just completed is
+// the 1-in-N sampled for debug-level logging, keeping per-request logs
+// affordable across a multi-hour steady-state run while still allowing
+// -log-sampling=1 during a short ramp-up.
+func (br *BenchmarkRunner) shouldSampleRequestLog() bool {
+	if *logSampling <= 1 {
+		return true
+	}
+	n := atomic.AddInt64(&br.requestLogCount, 1)
+	return n%int64(*logSampling) == 0
+}
+
+// requestLoggerCtxKey is the context.Context key withRequestLogger attaches
+// a per-request *slog.Logger under.
+type requestLoggerCtxKey struct{}
+
+// withRequestLogger attaches logger to ctx so code called deeper in the
+// stack for this request (a storage driver, the download manager, a future
+// SDK middleware hook) can recover the exact fields this request was logged
+// with via loggerFromContext, instead of needing a *slog.Logger threaded
+// through every signature on the call path.
+func withRequestLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, requestLoggerCtxKey{}, logger)
+}
+
+// loggerFromContext recovers the logger attached by withRequestLogger,
+// falling back to slog.Default() when ctx carries none (e.g. a call made
+// outside of a request, like the startup ObjectExists check).
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(requestLoggerCtxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// dedupEntry tracks how many times a (level, message) pair has repeated
+// since the last flush, plus the attributes of the occurrence that will be
+// folded into the eventual summary line.
+type dedupEntry struct {
+	level   slog.Level
+	message string
+	attrs   []slog.Attr
+	count   int64
+}
+
+// dedupState is the dedup bookkeeping shared by a dedupHandler and every
+// handler derived from it via WithAttrs/WithGroup, so a per-worker or
+// per-request logger still dedupes against (and flushes alongside) every
+// other logger descended from the same root instead of starting its own
+// orphaned, never-flushed copy.
+type dedupState struct {
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*dedupEntry
+}
+
+// dedupHandler wraps another slog.Handler and suppresses repeated identical
+// log lines (same level and message), letting the first occurrence through
+// immediately and then emitting one summary record with a suppressed_count
+// attribute every flushInterval instead of every repeat, mirroring the
+// Deduper pattern from Prometheus's own slog migration. This keeps a
+// steady-state run's "Results channel full, dropping result" warning (or
+// any other condition that fires on every request) from drowning out
+// everything else in the log.
+type dedupHandler struct {
+	next  slog.Handler
+	state *dedupState
+}
+
+// newDedupHandler wraps next, flushing suppressed counts every
+// flushInterval on a background goroutine that runs for the life of the
+// process (the logger it backs is never torn down).
+func newDedupHandler(next slog.Handler, flushInterval time.Duration) *dedupHandler {
+	state := &dedupState{flushInterval: flushInterval, pending: make(map[string]*dedupEntry)}
+	h := &dedupHandler{next: next, state: state}
+	go h.flushLoop()
+	return h
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, record slog.Record) error {
+	key := fmt.Sprintf("%d:%s", record.Level, record.Message)
+
+	h.state.mu.Lock()
+	if _, seen := h.state.pending[key]; !seen {
+		attrs := make([]slog.Attr, 0, record.NumAttrs())
+		record.Attrs(func(a slog.Attr) bool {
+			attrs = append(attrs, a)
+			return true
+		})
+		h.state.pending[key] = &dedupEntry{level: record.Level, message: record.Message, attrs: attrs}
+		h.state.mu.Unlock()
+		// Let the first occurrence through immediately: a one-off error
+		// shouldn't wait up to flushInterval to be seen.
+		return h.next.Handle(ctx, record)
+	}
+	h.state.pending[key].count++
+	h.state.mu.Unlock()
+	return nil
+}
+
+func (h *dedupHandler) flushLoop() {
+	ticker := time.NewTicker(h.state.flushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		h.flush()
+	}
+}
+
+func (h *dedupHandler) flush() {
+	h.state.mu.Lock()
+	due := h.state.pending
+	h.state.pending = make(map[string]*dedupEntry)
+	h.state.mu.Unlock()
+
+	for _, entry := range due {
+		if entry.count == 0 {
+			continue
+		}
+		summary := slog.NewRecord(time.Now(), entry.level, entry.message, 0)
+		summary.AddAttrs(entry.attrs...)
+		summary.AddAttrs(slog.Int64("suppressed_count", entry.count))
+		_ = h.next.Handle(context.Background(), summary)
+	}
+}
+
+// WithAttrs and WithGroup must not construct a fresh dedupHandler: that
+// would give every per-worker and per-request derived logger (see
+// worker/openLoopWorker/executeRequest) its own orphaned dedupState and no
+// flushLoop goroutine, so nothing derived from the root logger would ever
+// actually get deduplicated. Instead they share this handler's dedupState
+// and only push the attrs/group onto next - deduplication keys on (level,
+// message) alone, so which logger instance attached the attributes
+// doesn't matter.
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dedupHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	return &dedupHandler{next: h.next.WithGroup(name), state: h.state}
+}
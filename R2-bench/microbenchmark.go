@@ -4,83 +4,146 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
-	"math/rand"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/time/rate"
+
 	"r2-bench/instances"
+	"r2-bench/pkg/download"
 	"r2-bench/storage"
+	"r2-bench/workload"
 )
 
 var (
-	url              = flag.String("url", "", "Storage endpoint URL (r2 or s3)")
-	instanceType     = flag.String("instance", "", "EC2 instance type")
-	bucketName       = flag.String("bucket", "", "Bucket name")
-	objectKey        = flag.String("object", "test-object-1gb", "Object key for testing")
-	objectSize       = flag.Int64("object-size", 1024*1024*1024, "Object size in bytes (default: 1GB)")
-	rangeSize        = flag.Int64("range-size", 100*1024*1024, "Range size in bytes (default: 100MB)")
-	steadyStateHours = flag.Int("steady-state-hours", 3, "Hours to run steady state test")
-	warmupMinutes    = flag.Int("warmup-minutes", 5, "Warmup duration in minutes")
-	rampStepMinutes  = flag.Int("ramp-step-minutes", 1, "Ramp step duration in minutes")
-	rampStepSize     = flag.Int("ramp-step-size", 10, "Concurrency increase per ramp step")
-	initialConcurrency = flag.Int("initial-concurrency", 10, "Initial concurrency level")
-	maxConcurrency   = flag.Int("max-concurrency", 200, "Maximum concurrency to test")
-	outputDir        = flag.String("output", "./output", "Output directory for results")
-	prometheusAddr   = flag.String("prometheus-addr", ":9100", "Prometheus metrics server address")
+	instanceType        = flag.String("instance", "", "EC2 instance type")
+	objectKey           = flag.String("object", "test-object-1gb", "Object key for testing")
+	objectSize          = flag.Int64("object-size", 1024*1024*1024, "Object size in bytes (default: 1GB)")
+	rangeSize           = flag.Int64("range-size", 100*1024*1024, "Range size in bytes (default: 100MB)")
+	steadyStateHours    = flag.Int("steady-state-hours", 3, "Hours to run steady state test")
+	warmupMinutes       = flag.Int("warmup-minutes", 5, "Warmup duration in minutes")
+	rampStepMinutes     = flag.Int("ramp-step-minutes", 1, "Ramp step duration in minutes")
+	rampStepSize        = flag.Int("ramp-step-size", 10, "Concurrency increase per ramp step")
+	initialConcurrency  = flag.Int("initial-concurrency", 10, "Initial concurrency level")
+	maxConcurrency      = flag.Int("max-concurrency", 200, "Maximum concurrency to test")
+	outputDir           = flag.String("output", "./output", "Output directory for results")
+	prometheusAddr      = flag.String("prometheus-addr", ":9100", "Prometheus metrics server address")
+	gradientThreshold   = flag.Float64("gradient-threshold", 0.95, "Minimum gradient*latency-penalty to keep increasing concurrency")
+	maxErrorRate        = flag.Float64("max-error-rate", 0.01, "Maximum acceptable error rate during the concurrency search")
+	minRTTMs            = flag.Float64("min-rtt-ms", 10, "Best-case RTT used as the baseline for the latency penalty")
+	workloadType        = flag.String("workload", "uniform", "Access pattern generator: uniform, sequential, zipfian, hotset, trace-replay")
+	workloadStride      = flag.Int64("workload-stride", 0, "Stride in bytes between reads for the sequential generator (default: range-size)")
+	workloadZipfTheta   = flag.Float64("workload-zipf-theta", 1.0, "Skew parameter for the zipfian generator")
+	workloadNumKeys     = flag.Int("workload-num-keys", 100, "Number of hot-key ranges for the zipfian generator")
+	workloadHotsetPct   = flag.Float64("workload-hotset-pct", 0.8, "Fraction of requests routed to the hot range for the hotset generator")
+	workloadHotRangePct = flag.Float64("workload-hot-range-pct", 0.2, "Fraction of the object treated as hot by the hotset generator")
+	workloadTraceFile   = flag.String("workload-trace-file", "", "CSV trace file (timestamp,key,offset,length) for the trace-replay generator")
+	mode                = flag.String("mode", "closed", "Request mode: closed (back-to-back) or open (paced to -target-rps)")
+	targetRPS           = flag.Float64("target-rps", 0, "Target requests/sec for open-loop mode (required when -mode=open)")
+	pendingQueueSize    = flag.Int("pending-queue-size", 1000, "Bounded queue size for open-loop mode; overflow counts as coordinated omission")
+	maxRPS              = flag.Float64("max-rps", 0, "Safety cap on requests/sec shared across all workers (0 = unlimited)")
+	maxBytesPerSec      = flag.Float64("max-bytes-per-sec", 0, "Safety cap on bytes/sec shared across all workers (0 = unlimited)")
+	runID               = flag.String("run-id", "", "Identifier stamped on every metric and result row so a dashboard can compare this run against others (default: a generated ULID)")
+	parquetMaxRows      = flag.Int("parquet-max-rows", 1000, "Flush a Parquet row group once this many results are buffered")
+	parquetMaxBytes     = flag.Int64("parquet-max-bytes", 64*1024*1024, "Flush a Parquet row group once roughly this many bytes are buffered")
+	parquetRotate       = flag.Duration("parquet-rotate-interval", time.Hour, "Rotate to a new Parquet file after this much wall-clock time")
+	parquetCompression  = flag.String("parquet-compression", "snappy", "Parquet compression codec: snappy, zstd, or none")
 )
 
 // BenchmarkRunner manages the benchmark execution
 type BenchmarkRunner struct {
-	client        interface {
-		GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
-		ObjectExists(ctx context.Context, objectKey string) (bool, error)
-		GetEndpoint() string
-	}
-	ec2Monitor    *instances.EC2Monitor
-	parquetWriter *storage.ParquetWriter
-	promExporter  *storage.PrometheusExporter
-	config        *Config
-	results       chan RequestResult
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
+	endpoints             []*Endpoint
+	ec2Monitor            *instances.EC2Monitor
+	promExporter          *storage.PrometheusExporter
+	concurrencyController *ConcurrencyController
+	generator             workload.Generator
+	downloadManager       *download.Manager
+	mode                  requestMode
+	pendingQueueSize      int
+	targetRPSLimiter      *rate.Limiter
+	maxRPSLimiter         *rate.Limiter
+	maxBytesLimiter       *rate.Limiter
+	config                *Config
+	results               chan RequestResult
+	resultsDone           chan struct{} // closed once collectResults has drained results
+	stopChan              chan struct{}
+	wg                    sync.WaitGroup
+
+	logger          *slog.Logger
+	requestLogCount int64 // atomic: counts completed requests for -log-sampling
+
+	stepMu        sync.Mutex
+	stepBytes     int64
+	stepLatencies []float64
+	stepErrors    int
+	stepTotal     int
+
+	// Runtime-steerable state: changed by the admin HTTP API or a watched
+	// -config file without restarting the process. See admin.go.
+	concurrencyTarget int64 // atomic: desired worker count per endpoint pool
+	rangeSizeOverride int64 // atomic: 0 means use config.RangeSize
+	paused            int32 // atomic bool: 1 while the current phase is paused
+
+	limiterMu sync.RWMutex // guards maxRPSLimiter (swapped by SetMaxRPS) and maxBytesLimiter's burst (resized by SetRangeSize)
+
+	phaseAbortMu sync.Mutex
+	phaseAbort   chan struct{} // closed to abort the current phase/step early
 }
 
 func main() {
 	flag.Parse()
 
-	if *url == "" || *instanceType == "" || *bucketName == "" {
-		log.Fatal("URL, instance type, and bucket name are required")
+	logger, err := newLogger()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
+		os.Exit(1)
 	}
+	slog.SetDefault(logger)
 
-	log.Printf("Starting R2 benchmark for %s on %s", *url, *instanceType)
-	log.Printf("Object: %s (%d bytes), Range size: %d bytes", *objectKey, *objectSize, *rangeSize)
-	log.Printf("Steady state: %d hours, Warmup: %d minutes", *steadyStateHours, *warmupMinutes)
+	if len(urlFlags) == 0 || *instanceType == "" || len(bucketFlags) == 0 {
+		logger.Error("At least one -url/-bucket pair and an instance type are required")
+		os.Exit(1)
+	}
+
+	logger.Info("Starting R2 benchmark", "endpoints", len(urlFlags), "instance_type", *instanceType)
+	logger.Info("Object configuration", "object", *objectKey, "object_size", *objectSize, "range_size", *rangeSize)
+	logger.Info("Schedule", "steady_state_hours", *steadyStateHours, "warmup_minutes", *warmupMinutes)
 
 	// Initialize components
-	runner, err := initializeBenchmark()
+	runner, err := initializeBenchmark(logger)
 	if err != nil {
-		log.Fatalf("Failed to initialize benchmark: %v", err)
+		logger.Error("Failed to initialize benchmark", "err", err)
+		os.Exit(1)
 	}
 	defer runner.cleanup()
+	logger.Info("Run ID assigned", "run_id", runner.config.RunID)
 
 	// Start Prometheus server
 	go func() {
-		log.Printf("Starting Prometheus server on %s", *prometheusAddr)
+		logger.Info("Starting Prometheus server", "addr", *prometheusAddr)
 		if err := runner.promExporter.StartServer(*prometheusAddr); err != nil {
-			log.Printf("Prometheus server error: %v", err)
+			logger.Error("Prometheus server error", "err", err)
 		}
 	}()
 
+	// Expose the admin control plane (concurrency/range-size/max-rps,
+	// pause/resume/abort) so a long steady-state run can be steered
+	// without restarting, and hot-reload -config if one was given.
+	NewAdminServer(runner).Start(*adminAddr)
+	watchConfigFile(*configFile, runner)
+
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigChan
-		log.Printf("Received shutdown signal, stopping benchmark...")
+		logger.Info("Received shutdown signal, stopping benchmark...")
 		close(runner.stopChan)
 	}()
 
@@ -88,138 +151,161 @@ func main() {
 	runner.runBenchmark()
 }
 
-func initializeBenchmark() (*BenchmarkRunner, error) {
-	// Initialize storage client
-	var client interface {
-		GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
-		ObjectExists(ctx context.Context, objectKey string) (bool, error)
-		GetEndpoint() string
+func initializeBenchmark(logger *slog.Logger) (*BenchmarkRunner, error) {
+	effectiveRunID := *runID
+	if effectiveRunID == "" {
+		effectiveRunID = ulid.Make().String()
 	}
 
-	var err error
-	if isR2Endpoint(*url) {
-		// Parse R2 credentials from environment
-		accountID := os.Getenv("R2_ACCOUNT_ID")
-		accessKeyID := os.Getenv("R2_ACCESS_KEY_ID")
-		secretAccessKey := os.Getenv("R2_SECRET_ACCESS_KEY")
-
-		if accountID == "" || accessKeyID == "" || secretAccessKey == "" {
-			return nil, fmt.Errorf("R2 credentials not found in environment variables")
-		}
-
-		client, err = instances.NewR2Client(accountID, accessKeyID, secretAccessKey, *bucketName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create R2 client: %v", err)
-		}
-	} else {
-		// S3 client
-		region := extractRegionFromURL(*url)
-		client, err = instances.NewS3Client(region, *bucketName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create S3 client: %v", err)
-		}
+	// Build one client + output writer per -url/-bucket pair so a single
+	// process can drive several storage backends side by side.
+	endpoints, err := buildEndpoints(urlFlags, bucketFlags, r2AccountFlags, r2AccessKeyFlags, r2SecretKeyFlags, *outputDir, effectiveRunID, *instanceType)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize EC2 monitor
 	ec2Monitor, err := instances.NewEC2Monitor()
 	if err != nil {
-		log.Printf("Warning: Failed to initialize EC2 monitor: %v", err)
+		logger.Warn("Failed to initialize EC2 monitor", "err", err)
 	}
 
-	// Initialize Parquet writer
-	parquetWriter, err := storage.NewParquetWriter(*outputDir, 1000)
+	// Initialize Prometheus exporter
+	promExporter := storage.NewPrometheusExporter()
+
+	// Initialize the access-pattern generator
+	generator, err := workload.New(*workloadType, workload.Config{
+		ObjectKey:   *objectKey,
+		ObjectSize:  *objectSize,
+		RangeSize:   *rangeSize,
+		Stride:      *workloadStride,
+		ZipfTheta:   *workloadZipfTheta,
+		NumKeys:     *workloadNumKeys,
+		HotsetPct:   *workloadHotsetPct,
+		HotRangePct: *workloadHotRangePct,
+		TraceFile:   *workloadTraceFile,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Parquet writer: %v", err)
+		return nil, fmt.Errorf("failed to create workload generator: %v", err)
 	}
 
-	// Initialize Prometheus exporter
-	promExporter := storage.NewPrometheusExporter()
+	runMode := requestMode(*mode)
+	if runMode == modeOpen && *targetRPS <= 0 {
+		return nil, fmt.Errorf("-target-rps must be > 0 when -mode=open")
+	}
 
 	// Create configuration
 	config := &Config{
-		URL:              *url,
-		InstanceType:     *instanceType,
-		RangeSize:        *rangeSize,
-		SteadyStateHours: *steadyStateHours,
-		BucketName:       *bucketName,
-		ObjectKey:        *objectKey,
-		ObjectSize:       *objectSize,
-		WarmupMinutes:    *warmupMinutes,
-		RampStepMinutes:  *rampStepMinutes,
-		RampStepSize:     *rampStepSize,
-		MaxConcurrency:   *maxConcurrency,
+		RunID:             effectiveRunID,
+		InstanceType:      *instanceType,
+		RangeSize:         *rangeSize,
+		SteadyStateHours:  *steadyStateHours,
+		ObjectKey:         *objectKey,
+		ObjectSize:        *objectSize,
+		WarmupMinutes:     *warmupMinutes,
+		RampStepMinutes:   *rampStepMinutes,
+		RampStepSize:      *rampStepSize,
+		MaxConcurrency:    *maxConcurrency,
+		GradientThreshold: *gradientThreshold,
+		MaxErrorRate:      *maxErrorRate,
+		MinRTTMs:          *minRTTMs,
 	}
 
 	return &BenchmarkRunner{
-		client:        client,
-		ec2Monitor:    ec2Monitor,
-		parquetWriter: parquetWriter,
-		promExporter:  promExporter,
-		config:        config,
-		results:       make(chan RequestResult, 10000),
-		stopChan:      make(chan struct{}),
+		endpoints:        endpoints,
+		ec2Monitor:       ec2Monitor,
+		promExporter:     promExporter,
+		generator:        generator,
+		mode:             runMode,
+		pendingQueueSize: *pendingQueueSize,
+		targetRPSLimiter: newLimiter(*targetRPS, 1),
+		maxRPSLimiter:    newLimiter(*maxRPS, 1),
+		maxBytesLimiter:  newLimiter(*maxBytesPerSec, int(*rangeSize)),
+		config:           config,
+		downloadManager:  download.NewManager(*dlManagerPartSize, *dlManagerConcurrency),
+		results:          make(chan RequestResult, 10000),
+		resultsDone:      make(chan struct{}),
+		stopChan:         make(chan struct{}),
+		logger:           logger,
 	}, nil
 }
 
 func (br *BenchmarkRunner) cleanup() {
 	close(br.results)
 	br.wg.Wait()
-	br.parquetWriter.Close()
+	<-br.resultsDone
+	for _, ep := range br.endpoints {
+		close(ep.resultChan)
+	}
+	for _, ep := range br.endpoints {
+		if err := <-ep.ingestDone; err != nil {
+			br.logger.Error("Error closing Parquet writer", "endpoint", ep.Label, "err", err)
+		}
+	}
 }
 
 func (br *BenchmarkRunner) runBenchmark() {
 	ctx := context.Background()
 
-	// Verify test object exists
-	exists, err := br.client.ObjectExists(ctx, br.config.ObjectKey)
-	if err != nil {
-		log.Fatalf("Failed to check object existence: %v", err)
-	}
-	if !exists {
-		log.Fatalf("Test object %s does not exist", br.config.ObjectKey)
+	// Verify the test object exists on every endpoint before starting
+	for _, ep := range br.endpoints {
+		exists, err := ep.Client.ObjectExists(ctx, br.config.ObjectKey)
+		if err != nil {
+			br.logger.Error("Failed to check object existence", "endpoint", ep.Label, "err", err)
+			os.Exit(1)
+		}
+		if !exists {
+			br.logger.Error("Test object does not exist", "object", br.config.ObjectKey, "endpoint", ep.Label)
+			os.Exit(1)
+		}
 	}
 
 	// Start result collector
 	go br.collectResults()
 
 	// Phase 1: Warmup
-	log.Printf("Phase 1: Warmup for %d minutes at concurrency %d", br.config.WarmupMinutes, *initialConcurrency)
+	br.logger.Info("Phase 1: Warmup", "minutes", br.config.WarmupMinutes, "concurrency", *initialConcurrency)
 	br.runPhase(ctx, *initialConcurrency, time.Duration(br.config.WarmupMinutes)*time.Minute, "warmup")
 
 	// Phase 2: Ramp-up
-	log.Printf("Phase 2: Ramp-up from %d to %d concurrency", *initialConcurrency, br.config.MaxConcurrency)
+	br.logger.Info("Phase 2: Ramp-up", "from", *initialConcurrency, "to", br.config.MaxConcurrency)
 	br.runRampUp(ctx, *initialConcurrency, br.config.MaxConcurrency)
 
 	// Phase 3: Steady state
 	optimalConcurrency := br.findOptimalConcurrency()
-	log.Printf("Phase 3: Steady state for %d hours at concurrency %d", br.config.SteadyStateHours, optimalConcurrency)
+	br.logger.Info("Phase 3: Steady state", "hours", br.config.SteadyStateHours, "concurrency", optimalConcurrency)
 	br.runPhase(ctx, optimalConcurrency, time.Duration(br.config.SteadyStateHours)*time.Hour, "steady-state")
 
-	log.Printf("Benchmark completed successfully")
+	br.logger.Info("Benchmark completed successfully")
 }
 
 func (br *BenchmarkRunner) runPhase(ctx context.Context, concurrency int, duration time.Duration, phase string) {
-	log.Printf("Starting %s phase with concurrency %d for %v", phase, concurrency, duration)
+	br.logger.Info("Starting phase", "phase", phase, "concurrency", concurrency, "duration", duration)
+
+	atomic.StoreInt64(&br.concurrencyTarget, int64(concurrency))
+	abortCh := br.beginPhase()
 
 	startTime := time.Now()
 	endTime := startTime.Add(duration)
+	phaseCtx, cancel := context.WithDeadline(ctx, endTime)
+	defer cancel()
 
 	// Start workers
-	for i := 0; i < concurrency; i++ {
-		br.wg.Add(1)
-		go br.worker(ctx, i, concurrency, endTime)
-	}
+	br.startWorkerPool(phaseCtx, endTime, phase)
 
 	// Monitor and collect metrics
 	go br.monitorPhase(concurrency, startTime, endTime)
 
-	// Wait for phase completion or stop signal
+	// Wait for phase completion, admin-triggered abort, or stop signal
 	select {
-	case <-time.After(duration):
-		log.Printf("%s phase completed", phase)
+	case <-phaseCtx.Done():
+		br.logger.Info("Phase completed", "phase", phase)
+	case <-abortCh:
+		br.logger.Info("Phase aborted via admin API", "phase", phase)
 	case <-br.stopChan:
-		log.Printf("%s phase stopped by user", phase)
+		br.logger.Info("Phase stopped by user", "phase", phase)
 	}
+	cancel()
 
 	// Stop workers
 	br.wg.Wait()
@@ -228,122 +314,336 @@ func (br *BenchmarkRunner) runPhase(ctx context.Context, concurrency int, durati
 func (br *BenchmarkRunner) runRampUp(ctx context.Context, startConcurrency, maxConcurrency int) {
 	currentConcurrency := startConcurrency
 	stepDuration := time.Duration(br.config.RampStepMinutes) * time.Minute
+	br.concurrencyController = NewConcurrencyController(br.config)
 
 	for currentConcurrency <= maxConcurrency {
-		log.Printf("Ramp step: testing concurrency %d for %v", currentConcurrency, stepDuration)
-		
+		br.logger.Info("Ramp step starting", "concurrency", currentConcurrency, "duration", stepDuration)
+
+		br.resetStepStats()
+		atomic.StoreInt64(&br.concurrencyTarget, int64(currentConcurrency))
+		abortCh := br.beginPhase()
 		startTime := time.Now()
 		endTime := startTime.Add(stepDuration)
+		stepCtx, cancel := context.WithDeadline(ctx, endTime)
 
 		// Start workers for this step
-		for i := 0; i < currentConcurrency; i++ {
-			br.wg.Add(1)
-			go br.worker(ctx, i, currentConcurrency, endTime)
-		}
+		br.startWorkerPool(stepCtx, endTime, "ramp-up")
 
 		// Monitor this step
 		go br.monitorPhase(currentConcurrency, startTime, endTime)
 
-		// Wait for step completion or stop signal
+		// Wait for step completion, admin-triggered abort, or stop signal
 		select {
-		case <-time.After(stepDuration):
-			log.Printf("Ramp step %d completed", currentConcurrency)
+		case <-stepCtx.Done():
+			br.logger.Info("Ramp step completed", "concurrency", currentConcurrency)
+		case <-abortCh:
+			br.logger.Info("Ramp step aborted via admin API", "concurrency", currentConcurrency)
 		case <-br.stopChan:
-			log.Printf("Ramp stopped by user at concurrency %d", currentConcurrency)
+			cancel()
+			br.logger.Info("Ramp stopped by user", "concurrency", currentConcurrency)
+			br.wg.Wait()
 			return
 		}
+		cancel()
 
 		// Stop workers for this step
 		br.wg.Wait()
 
-		// Increase concurrency for next step
-		currentConcurrency += br.config.RampStepSize
+		throughputBps, p50Ms, p99Ms, errorRate := br.stepMetrics(time.Since(startTime))
+		next, done := br.concurrencyController.Evaluate(currentConcurrency, throughputBps, p50Ms, p99Ms, errorRate)
+		for _, ep := range br.endpoints {
+			br.promExporter.UpdateTargetConcurrency(br.config.RunID, br.config.InstanceType, br.concurrencyController.BestConcurrency(), ep.Label)
+		}
+		if done {
+			br.logger.Info("Concurrency search converged", "concurrency", br.concurrencyController.BestConcurrency(), "throughput_mbps", throughputBps/(1024*1024))
+			return
+		}
+
+		currentConcurrency = next
+	}
+}
+
+// resetStepStats clears the per-step accumulators consulted by stepMetrics
+// once the in-flight workers for that step have drained.
+func (br *BenchmarkRunner) resetStepStats() {
+	br.stepMu.Lock()
+	defer br.stepMu.Unlock()
+	br.stepBytes = 0
+	br.stepLatencies = br.stepLatencies[:0]
+	br.stepErrors = 0
+	br.stepTotal = 0
+}
+
+// stepMetrics summarizes the requests observed during the most recently
+// completed ramp step: throughput in bytes/sec, p50/p99 latency in
+// milliseconds, and error rate.
+func (br *BenchmarkRunner) stepMetrics(elapsed time.Duration) (throughputBps, p50Ms, p99Ms, errorRate float64) {
+	br.stepMu.Lock()
+	defer br.stepMu.Unlock()
+
+	if elapsed > 0 {
+		throughputBps = float64(br.stepBytes) / elapsed.Seconds()
+	}
+	p50Ms = percentile(append([]float64{}, br.stepLatencies...), 50)
+	p99Ms = percentile(append([]float64{}, br.stepLatencies...), 99)
+	if br.stepTotal > 0 {
+		errorRate = float64(br.stepErrors) / float64(br.stepTotal)
 	}
+	return
 }
 
 func (br *BenchmarkRunner) findOptimalConcurrency() int {
-	// This is a simplified implementation
-	// In practice, you'd analyze the metrics collected during ramp-up
-	// to find the concurrency level that provides the best throughput
-	// without causing excessive latency or errors
-	
-	// For now, return a reasonable default
-	return 50
+	if br.concurrencyController == nil || br.concurrencyController.BestConcurrency() == 0 {
+		return 50
+	}
+
+	if trace := br.concurrencyController.Trace(); len(trace) > 0 {
+		if path, err := WriteTrace(*outputDir, trace); err != nil {
+			br.logger.Error("Error writing concurrency search trace", "err", err)
+		} else {
+			br.logger.Info("Wrote concurrency search trace", "path", path)
+		}
+	}
+
+	return br.concurrencyController.BestConcurrency()
+}
+
+// startWorkerPool launches the per-endpoint worker pools for the upcoming
+// phase/step, in either closed-loop (back-to-back requests) or open-loop
+// (paced to a target RPS via runOpenLoopDispatcher) mode. Each pool is
+// managed by runScalingPool, which tracks concurrencyTarget so an
+// admin-driven concurrency change takes effect immediately instead of
+// waiting for the next phase. It fans out one independent pool per
+// configured endpoint, all sharing ctx's deadline as the same phase clock,
+// so a comparative run (e.g. R2 vs S3) measures both backends under
+// identical conditions.
+func (br *BenchmarkRunner) startWorkerPool(ctx context.Context, endTime time.Time, phase string) {
+	for _, ep := range br.endpoints {
+		ep := ep
+		if br.mode != modeOpen {
+			go br.runScalingPool(ctx, func(wctx context.Context, id, concurrency int) {
+				br.wg.Add(1)
+				go br.worker(wctx, ep, id, concurrency, phase)
+			})
+			continue
+		}
+
+		pending := make(chan scheduledRequest, br.pendingQueueSize)
+		go br.runOpenLoopDispatcher(ctx, ep, pending, endTime)
+		go br.runScalingPool(ctx, func(wctx context.Context, id, concurrency int) {
+			br.wg.Add(1)
+			go br.openLoopWorker(wctx, ep, id, concurrency, pending, phase)
+		})
+	}
+}
+
+// runScalingPool keeps a set of worker goroutines whose count tracks
+// concurrencyTarget, calling spawn in a fresh cancelable context when the
+// target grows and canceling the most recently started worker when it
+// shrinks. It returns once ctx is done.
+func (br *BenchmarkRunner) runScalingPool(ctx context.Context, spawn func(wctx context.Context, id, concurrency int)) {
+	var cancels []context.CancelFunc
+	nextID := 0
+
+	rescale := func() {
+		target := int(atomic.LoadInt64(&br.concurrencyTarget))
+		for len(cancels) < target {
+			wctx, cancel := context.WithCancel(ctx)
+			cancels = append(cancels, cancel)
+			spawn(wctx, nextID, target)
+			nextID++
+		}
+		for len(cancels) > target {
+			last := len(cancels) - 1
+			cancels[last]()
+			cancels = cancels[:last]
+		}
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	rescale()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rescale()
+		}
+	}
 }
 
-func (br *BenchmarkRunner) worker(ctx context.Context, threadID, concurrency int, endTime time.Time) {
+func (br *BenchmarkRunner) worker(ctx context.Context, ep *Endpoint, threadID, concurrency int, phase string) {
 	defer br.wg.Done()
+	logger := br.logger.With("thread_id", threadID, "phase", phase, "endpoint", ep.Label)
 
-	for time.Now().Before(endTime) {
+	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-br.stopChan:
 			return
 		default:
 		}
 
-		// Generate random range within object bounds
-		maxStart := br.config.ObjectSize - br.config.RangeSize
-		if maxStart <= 0 {
-			log.Printf("Range size %d is larger than object size %d", br.config.RangeSize, br.config.ObjectSize)
+		if !br.waitWhilePaused(ctx) {
 			return
 		}
 
-		rangeStart := rand.Int63n(maxStart)
-		rangeLen := br.config.RangeSize
-		if rangeStart+rangeLen > br.config.ObjectSize {
-			rangeLen = br.config.ObjectSize - rangeStart
-		}
-
-		// Make request
 		reqStart := time.Now()
-		data, err := br.client.GetObjectRange(ctx, br.config.ObjectKey, rangeStart, rangeLen)
-		latency := time.Since(reqStart)
-
-		// Record result
-		result := RequestResult{
-			Timestamp:    reqStart,
-			ThreadID:     threadID,
-			ConnID:       threadID % concurrency,
-			ObjectKey:    br.config.ObjectKey,
-			RangeStart:   rangeStart,
-			RangeLen:     rangeLen,
-			Bytes:        int64(len(data)),
-			LatencyMs:    float64(latency.Microseconds()) / 1000.0,
-			HTTPStatus:   200,
-			RetryCount:   0,
-			InstanceType: br.config.InstanceType,
-			Concurrency:  concurrency,
+		if err := br.applySafetyLimits(ctx, br.effectiveRangeSize()); err != nil {
+			return
 		}
-
-		if err != nil {
-			result.ErrMsg = err.Error()
-			result.HTTPStatus = 500
+		if ok := br.executeRequest(ctx, ep, threadID, concurrency, reqStart, logger); !ok {
+			return
 		}
+	}
+}
+
+// openLoopWorker pulls requests from the shared pending queue as fast as it
+// can serve them, recording the original scheduledAt so the eventual
+// latency can be split into queueing delay vs server latency.
+func (br *BenchmarkRunner) openLoopWorker(ctx context.Context, ep *Endpoint, threadID, concurrency int, pending <-chan scheduledRequest, phase string) {
+	defer br.wg.Done()
+	logger := br.logger.With("thread_id", threadID, "phase", phase, "endpoint", ep.Label)
 
-		// Send result
+	for {
 		select {
-		case br.results <- result:
-		default:
-			log.Printf("Warning: Results channel full, dropping result")
+		case <-ctx.Done():
+			return
+		case <-br.stopChan:
+			return
+		case req, more := <-pending:
+			if !more {
+				return
+			}
+			if !br.waitWhilePaused(ctx) {
+				return
+			}
+			if err := br.applySafetyLimits(ctx, br.effectiveRangeSize()); err != nil {
+				return
+			}
+			if ok := br.executeRequest(ctx, ep, threadID, concurrency, req.scheduledAt, logger); !ok {
+				return
+			}
 		}
 	}
 }
 
+// executeRequest issues a single range read against the configured access
+// pattern and records the resulting RequestResult(s). scheduledAt is the
+// time the request was meant to be sent (equal to the actual send time in
+// closed loop, and the dispatcher's target time in open loop). Every
+// request gets a ULID RequestID, and a logger carrying it plus conn_id,
+// concurrency, object_key, and range_start is attached to ctx via
+// withRequestLogger so anything called for this request - the download
+// manager, a future storage-driver hook - can recover the same fields via
+// loggerFromContext instead of needing a *slog.Logger parameter threaded
+// through its own signature. The AWS SDK's own retry/request logging
+// (objstore's s3 driver, gated by its log_retries/log_requests settings)
+// doesn't look at this context key - it logs through slog.Default()
+// instead - so correlating an SDK-level retry with this request still
+// relies on timing and object_key, not a shared RequestID.
+//
+// Ranges larger than -dl-manager-threshold are fetched in parallel
+// sub-ranges via the download manager (see download_manager.go), which
+// records one row per sub-range instead of one row for the whole range, so
+// a head-of-line stall on one connection shows up instead of being
+// averaged into the overall latency.
+func (br *BenchmarkRunner) executeRequest(ctx context.Context, ep *Endpoint, threadID, concurrency int, scheduledAt time.Time, logger *slog.Logger) bool {
+	objectKey, rangeStart, rangeLen := br.generator.NextRequest(ctx)
+	if rangeLen <= 0 {
+		logger.Error("Range size larger than object size", "range_size", br.config.RangeSize, "object_size", br.config.ObjectSize)
+		return false
+	}
+
+	requestID := ulid.Make().String()
+	reqLogger := logger.With("conn_id", threadID%concurrency, "concurrency", concurrency, "object_key", objectKey, "range_start", rangeStart, "request_id", requestID)
+	ctx = withRequestLogger(ctx, reqLogger)
+
+	if rangeLen > *dlManagerThreshold {
+		br.executeDownloadManagerRequest(ctx, ep, threadID, concurrency, scheduledAt, requestID, objectKey, rangeStart, rangeLen, reqLogger)
+		return true
+	}
+
+	reqStart := time.Now()
+	data, err := ep.Client.GetObjectRange(ctx, objectKey, rangeStart, rangeLen)
+	latency := time.Since(reqStart)
+
+	result := RequestResult{
+		Timestamp:      reqStart,
+		ScheduledAt:    scheduledAt,
+		ThreadID:       threadID,
+		ConnID:         threadID % concurrency,
+		ObjectKey:      objectKey,
+		RangeStart:     rangeStart,
+		RangeLen:       rangeLen,
+		Bytes:          int64(len(data)),
+		LatencyMs:      float64(latency.Microseconds()) / 1000.0,
+		HTTPStatus:     200,
+		RetryCount:     0,
+		InstanceType:   br.config.InstanceType,
+		Concurrency:    concurrency,
+		WorkloadType:   br.generator.Name(),
+		WorkloadParams: br.generator.Params(),
+		Endpoint:       ep.Label,
+		RequestID:      requestID,
+		RunID:          br.config.RunID,
+	}
+
+	if err != nil {
+		result.ErrMsg = err.Error()
+		result.HTTPStatus = 500
+		reqLogger.Error("Request failed", "err", err)
+	} else if br.shouldSampleRequestLog() {
+		reqLogger.Debug("Request completed", "latency_ms", result.LatencyMs, "bytes", result.Bytes)
+	}
+
+	br.sendResult(result)
+	return true
+}
+
+func (br *BenchmarkRunner) sendResult(result RequestResult) {
+	select {
+	case br.results <- result:
+	default:
+		br.logger.Warn("Results channel full, dropping result", "request_id", result.RequestID)
+	}
+}
+
 func (br *BenchmarkRunner) collectResults() {
+	defer close(br.resultsDone)
+
 	for result := range br.results {
-		// Write to Parquet
-		if err := br.parquetWriter.WriteResult(result); err != nil {
-			log.Printf("Error writing result: %v", err)
+		// Hand off to the originating endpoint's own Parquet ingest
+		// goroutine, so a comparative run produces side-by-side output
+		// per backend. The channel send provides backpressure: a slow
+		// disk stalls collectResults rather than dropping rows.
+		if ep := br.endpointByLabel(result.Endpoint); ep != nil {
+			ep.resultChan <- toRecord(result)
 		}
 
 		// Update Prometheus metrics
 		br.promExporter.RecordRequest(
+			result.RunID,
 			result.InstanceType,
 			result.Concurrency,
 			result.LatencyMs,
 			result.HTTPStatus,
 			result.ErrMsg,
+			result.Endpoint,
 		)
+
+		// Feed the adaptive concurrency controller's rolling stats
+		br.stepMu.Lock()
+		br.stepBytes += result.Bytes
+		br.stepLatencies = append(br.stepLatencies, result.LatencyMs)
+		br.stepTotal++
+		if result.ErrMsg != "" {
+			br.stepErrors++
+		}
+		br.stepMu.Unlock()
 	}
 }
 
@@ -354,18 +654,23 @@ func (br *BenchmarkRunner) monitorPhase(concurrency int, startTime, endTime time
 	for time.Now().Before(endTime) {
 		select {
 		case <-ticker.C:
-			// Update Prometheus metrics
-			br.promExporter.UpdateConcurrency(br.config.InstanceType, concurrency)
+			for _, ep := range br.endpoints {
+				br.promExporter.UpdateConcurrency(br.config.RunID, br.config.InstanceType, concurrency, ep.Label)
+			}
 
-			// Collect system stats if available
+			// Collect host-level system stats if available; these are
+			// shared across endpoints since they describe this machine,
+			// not a particular backend.
 			if br.ec2Monitor != nil {
 				if stats, err := br.ec2Monitor.GetSystemStats(); err == nil {
-					br.promExporter.UpdateCPUStats(br.config.InstanceType, stats.CPUUtilization)
+					br.promExporter.UpdateCPUStats(br.config.RunID, br.config.InstanceType, stats.CPUUtilization, "host")
 					if stats.NetworkStats != nil {
 						br.promExporter.UpdateNetworkStats(
+							br.config.RunID,
 							br.config.InstanceType,
 							stats.NetworkStats.LinkUtilPct,
 							float64(stats.NetworkStats.Retransmits),
+							"host",
 						)
 					}
 				}
@@ -375,3 +680,15 @@ func (br *BenchmarkRunner) monitorPhase(concurrency int, startTime, endTime time
 		}
 	}
 }
+
+// endpointByLabel looks up a configured endpoint by its metric/Parquet
+// label, returning nil if the run has no matching endpoint (e.g. a
+// synthetic result that predates any real request).
+func (br *BenchmarkRunner) endpointByLabel(label string) *Endpoint {
+	for _, ep := range br.endpoints {
+		if ep.Label == label {
+			return ep
+		}
+	}
+	return nil
+}
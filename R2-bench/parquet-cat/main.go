@@ -0,0 +1,45 @@
+// Command parquet-cat dumps a benchmark result Parquet file (written by
+// pkg/results.ParquetWriter) to stdout as newline-delimited JSON, for
+// inspecting a run's raw rows without loading them into a BI tool.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"r2-bench/pkg/results"
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: parquet-cat <path-to.parquet>")
+		os.Exit(1)
+	}
+
+	r, err := results.OpenReader(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parquet-cat: %v\n", err)
+		os.Exit(1)
+	}
+	defer r.Close()
+
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "parquet-cat: %v\n", err)
+			os.Exit(1)
+		}
+		if err := enc.Encode(rec); err != nil {
+			fmt.Fprintf(os.Stderr, "parquet-cat: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
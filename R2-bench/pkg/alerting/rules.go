@@ -0,0 +1,81 @@
+// Package alerting generates Prometheus rule files (recording rules and
+// alerts) for the benchmark's metrics, alongside the dashboards built by
+// pkg/dashboard. Its main entry point, BuildSLORuleGroups, implements the
+// Google SRE workbook's multi-window, multi-burn-rate alerting strategy so
+// an SLO breach pages before the error budget is actually exhausted.
+package alerting
+
+import "fmt"
+
+// RuleFile is the root of a Prometheus rule file, loaded via
+// `rule_files:` in prometheus.yml or uploaded to a Mimir/Grafana Cloud
+// ruler.
+type RuleFile struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a named, independently-evaluated set of rules.
+type RuleGroup struct {
+	Name     string `yaml:"name"`
+	Interval string `yaml:"interval,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+// Rule is either a recording rule (Record set) or an alerting rule (Alert
+// set); exactly one of the two is expected to be non-empty.
+type Rule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Builder assembles a RuleFile from AddGroup calls. Build validates the
+// result (unique, non-empty group names; every rule sets Record xor Alert,
+// plus Expr) instead of shipping a rule file Prometheus would refuse to
+// load.
+type Builder struct {
+	file *RuleFile
+}
+
+// NewRuleFile starts an empty rule file builder.
+func NewRuleFile() *Builder {
+	return &Builder{file: &RuleFile{}}
+}
+
+// AddGroup appends a rule group to the file.
+func (b *Builder) AddGroup(g RuleGroup) *Builder {
+	b.file.Groups = append(b.file.Groups, g)
+	return b
+}
+
+// Build validates the assembled rule file and returns it.
+func (b *Builder) Build() (*RuleFile, error) {
+	seen := make(map[string]bool, len(b.file.Groups))
+	for _, g := range b.file.Groups {
+		if g.Name == "" {
+			return nil, fmt.Errorf("alerting: group name is required")
+		}
+		if seen[g.Name] {
+			return nil, fmt.Errorf("alerting: duplicate group name %q", g.Name)
+		}
+		seen[g.Name] = true
+		if len(g.Rules) == 0 {
+			return nil, fmt.Errorf("alerting: group %q: at least one rule is required", g.Name)
+		}
+		for _, r := range g.Rules {
+			if r.Alert == "" && r.Record == "" {
+				return nil, fmt.Errorf("alerting: group %q: rule must set alert or record", g.Name)
+			}
+			if r.Alert != "" && r.Record != "" {
+				return nil, fmt.Errorf("alerting: group %q: rule %q: alert and record are mutually exclusive", g.Name, r.Alert)
+			}
+			if r.Expr == "" {
+				return nil, fmt.Errorf("alerting: group %q: rule %q: expr is required", g.Name, r.Alert+r.Record)
+			}
+		}
+	}
+	return b.file, nil
+}
@@ -0,0 +1,129 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BurnRateWindow is one entry in the Google SRE workbook's multi-window,
+// multi-burn-rate alerting strategy: comparing the error ratio over a short
+// and a long lookback window against the same threshold catches both a
+// fast, budget-exhausting outage and a slow, sustained degradation without
+// needing a separate alert for each.
+type BurnRateWindow struct {
+	Short    string  // short lookback, e.g. "5m"
+	Long     string  // long lookback, e.g. "1h"
+	For      string  // how long the condition must hold before the alert fires
+	BurnRate float64 // alert once the error ratio exceeds the SLO's budget times this
+	Severity string
+}
+
+// defaultBurnRateWindows is the workbook's standard 4-window ladder for a
+// 28-day SLO window (https://sre.google/workbook/alerting-on-slos/): the
+// two short pairs catch outages fast at the cost of a short-lived blip
+// occasionally paging; the two long pairs catch slow burns that would
+// otherwise exhaust the budget unnoticed.
+var defaultBurnRateWindows = []BurnRateWindow{
+	{Short: "5m", Long: "1h", For: "2m", BurnRate: 14.4, Severity: "critical"},
+	{Short: "30m", Long: "6h", For: "15m", BurnRate: 6, Severity: "critical"},
+	{Short: "2h", Long: "1d", For: "1h", BurnRate: 3, Severity: "warning"},
+	{Short: "6h", Long: "3d", For: "3h", BurnRate: 1, Severity: "warning"},
+}
+
+// SLO describes an error-budget-based service level objective over a
+// ratio-style metric (bad events / total events).
+type SLO struct {
+	// Name identifies the SLO in rule, group, and label names, e.g.
+	// "availability" or "read-latency".
+	Name string
+	// ErrorRatioQuery is a PromQL expression returning the fraction of bad
+	// events over the trailing window; every occurrence of "{{window}}" is
+	// replaced with the lookback, e.g.
+	// `rate(r2_bench_errors_total[{{window}}]) / rate(r2_bench_requests_total[{{window}}])`.
+	ErrorRatioQuery string
+	// Objective is the target fraction of good events, e.g. 0.999 for
+	// "three nines"; 1-Objective is the error budget.
+	Objective float64
+	// Windows overrides the default 4-window burn-rate ladder.
+	Windows []BurnRateWindow
+}
+
+func (s SLO) windows() []BurnRateWindow {
+	if len(s.Windows) > 0 {
+		return s.Windows
+	}
+	return defaultBurnRateWindows
+}
+
+func (s SLO) errorRatioExpr(window string) string {
+	return strings.ReplaceAll(s.ErrorRatioQuery, "{{window}}", window)
+}
+
+// BuildSLORuleGroups returns the recording-rule group (one series per
+// distinct lookback window, so every burn-rate alert shares the same
+// computed ratio instead of re-running rate() per alert) and the
+// burn-rate alerting-rule group for s.
+func BuildSLORuleGroups(s SLO) (recording RuleGroup, alerts RuleGroup) {
+	slug := slugify(s.Name)
+	budget := 1 - s.Objective
+
+	recording = RuleGroup{Name: fmt.Sprintf("%s-error-ratio", slug)}
+	seenWindows := make(map[string]bool)
+	for _, w := range s.windows() {
+		for _, window := range [2]string{w.Short, w.Long} {
+			if seenWindows[window] {
+				continue
+			}
+			seenWindows[window] = true
+			recording.Rules = append(recording.Rules, Rule{
+				Record: fmt.Sprintf("%s:error_ratio:rate%s", slug, window),
+				Expr:   s.errorRatioExpr(window),
+				Labels: map[string]string{"slo": s.Name},
+			})
+		}
+	}
+
+	alerts = RuleGroup{Name: fmt.Sprintf("%s-burn-rate", slug)}
+	for _, w := range s.windows() {
+		threshold := budget * w.BurnRate
+		alerts.Rules = append(alerts.Rules, Rule{
+			Alert: pascalCase(s.Name) + "ErrorBudgetBurn" + pascalCase(w.Severity),
+			Expr: fmt.Sprintf(
+				"%s:error_ratio:rate%s > %g and %s:error_ratio:rate%s > %g",
+				slug, w.Short, threshold, slug, w.Long, threshold,
+			),
+			For: w.For,
+			Labels: map[string]string{
+				"severity": w.Severity,
+				"slo":      s.Name,
+			},
+			Annotations: map[string]string{
+				"summary":     fmt.Sprintf("%s error budget is burning %gx over %s/%s", s.Name, w.BurnRate, w.Short, w.Long),
+				"description": fmt.Sprintf("The %q SLO (objective %.3f%%) is burning its error budget at %gx the sustainable rate, sampled over %s and %s windows.", s.Name, s.Objective*100, w.BurnRate, w.Short, w.Long),
+			},
+		})
+	}
+
+	return recording, alerts
+}
+
+// slugify turns an SLO name into a metric-name-safe stem, e.g.
+// "read-latency" -> "read_latency".
+func slugify(name string) string {
+	return strings.NewReplacer("-", "_", " ", "_").Replace(name)
+}
+
+// pascalCase turns a hyphen/underscore/space-separated name into PascalCase
+// for use in an alert name, e.g. "read-latency" -> "ReadLatency".
+func pascalCase(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	})
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
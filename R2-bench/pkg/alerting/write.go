@@ -0,0 +1,26 @@
+package alerting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteRuleFile marshals file as Prometheus rule-file YAML and writes it to
+// path, creating parent directories as needed, for operators who load rules
+// via `rule_files:` in prometheus.yml rather than a ruler API.
+func WriteRuleFile(file *RuleFile, path string) error {
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("alerting: marshal rule file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("alerting: create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("alerting: write %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validUnits is the subset of Grafana's built-in unit identifiers this
+// generator is expected to use; WithUnit rejects anything else so a typo
+// doesn't silently render as "none" in Grafana.
+var validUnits = map[string]bool{
+	"short":       true,
+	"none":        true,
+	"percent":     true,
+	"percentunit": true,
+	"Mbps":        true,
+	"bps":         true,
+	"bytes":       true,
+	"decbytes":    true,
+	"ms":          true,
+	"s":           true,
+	"reqps":       true,
+}
+
+// minSchemaVersion/maxSchemaVersion bound the Grafana dashboard
+// schemaVersions this generator has been exercised against (Grafana 7
+// through 10).
+const (
+	minSchemaVersion     = 16
+	maxSchemaVersion     = 39
+	defaultSchemaVersion = 30
+)
+
+var defaultRefreshIntervals = []string{"5s", "10s", "30s", "1m", "5m", "15m", "30m", "1h", "2h", "1d"}
+
+// Builder assembles a GrafanaDashboard from NewDashboard(title), chained
+// With* calls, and panels added via AddPanel. Build validates the result
+// and returns an error instead of a dashboard that would fail or render
+// incorrectly on import.
+type Builder struct {
+	dash          *GrafanaDashboard
+	panelBuilders []*PanelBuilder
+}
+
+// NewDashboard starts a dashboard builder with the defaults this generator
+// has always shipped: dark theme, browser timezone, a 1-hour window
+// refreshing every 10s.
+func NewDashboard(title string) *Builder {
+	return &Builder{
+		dash: &GrafanaDashboard{
+			Dashboard: DashboardConfig{
+				Title:         title,
+				Tags:          []string{},
+				Style:         "dark",
+				Timezone:      "browser",
+				SchemaVersion: defaultSchemaVersion,
+				Version:       1,
+				Refresh:       "10s",
+				Time:          TimeRange{From: "now-1h", To: "now"},
+				Timepicker:    Timepicker{RefreshIntervals: defaultRefreshIntervals},
+				Templating:    Templating{List: []interface{}{}},
+				Annotations:   Annotations{List: []interface{}{}},
+				Links:         []Link{},
+			},
+			Overwrite: true,
+		},
+	}
+}
+
+// WithTag appends a dashboard search tag.
+func (b *Builder) WithTag(tag string) *Builder {
+	b.dash.Dashboard.Tags = append(b.dash.Dashboard.Tags, tag)
+	return b
+}
+
+// WithRefresh overrides the dashboard's auto-refresh interval (default "10s").
+func (b *Builder) WithRefresh(interval string) *Builder {
+	b.dash.Dashboard.Refresh = interval
+	return b
+}
+
+// WithTimeRange overrides the default "now-1h" to "now" window.
+func (b *Builder) WithTimeRange(from, to string) *Builder {
+	b.dash.Dashboard.Time = TimeRange{From: from, To: to}
+	return b
+}
+
+// WithSchemaVersion overrides the default schemaVersion, e.g. to target an
+// older or newer Grafana release.
+func (b *Builder) WithSchemaVersion(version int) *Builder {
+	b.dash.Dashboard.SchemaVersion = version
+	return b
+}
+
+// WithVariable appends a templating variable (e.g. an instance_type or
+// endpoint selector driven by label_values()).
+func (b *Builder) WithVariable(v TemplateVariable) *Builder {
+	b.dash.Dashboard.Templating.List = append(b.dash.Dashboard.Templating.List, v)
+	return b
+}
+
+// WithQueryVariable appends a "query" template variable driven by a
+// label_values()-style Prometheus query, multi-select; no "All" option is
+// added (TemplateVariable has no includeAll field), e.g. WithQueryVariable("run_id", `label_values(r2_bench_throughput_mbps, run_id)`).
+func (b *Builder) WithQueryVariable(name, query string) *Builder {
+	return b.WithVariable(TemplateVariable{
+		Name:    name,
+		Label:   name,
+		Type:    "query",
+		Query:   query,
+		Refresh: 2,
+		Multi:   true,
+	})
+}
+
+// WithIntervalVariable appends an "interval" template variable (e.g. the
+// $range used in a rate()/increase() window), with values offered as the
+// literal strings passed in.
+func (b *Builder) WithIntervalVariable(name string, values []string) *Builder {
+	options := make([]VariableOption, len(values))
+	for i, v := range values {
+		options[i] = VariableOption{Text: v, Value: v}
+	}
+	current := VariableOption{}
+	if len(values) > 0 {
+		current = options[0]
+	}
+	return b.WithVariable(TemplateVariable{
+		Name:    name,
+		Label:   name,
+		Type:    "interval",
+		Query:   strings.Join(values, ","),
+		Options: options,
+		Current: current,
+	})
+}
+
+// AddPanel queues a panel builder to be built and placed on the dashboard.
+// Panels are built, ID-assigned, and validated in Build, in the order they
+// were added.
+func (b *Builder) AddPanel(p *PanelBuilder) *Builder {
+	b.panelBuilders = append(b.panelBuilders, p)
+	return b
+}
+
+// Build finalizes every queued panel, assigns IDs to any panel that didn't
+// set one explicitly, and validates the result: unique panel IDs,
+// non-overlapping GridPos, and a schemaVersion in the supported range.
+// Nothing is written to JSON until this succeeds.
+func (b *Builder) Build() (*GrafanaDashboard, error) {
+	if b.dash.Dashboard.Title == "" {
+		return nil, fmt.Errorf("dashboard: title is required")
+	}
+	if v := b.dash.Dashboard.SchemaVersion; v < minSchemaVersion || v > maxSchemaVersion {
+		return nil, fmt.Errorf("dashboard %q: schemaVersion %d outside supported range [%d, %d]", b.dash.Dashboard.Title, v, minSchemaVersion, maxSchemaVersion)
+	}
+
+	panels := make([]Panel, 0, len(b.panelBuilders))
+	seenIDs := make(map[int]bool)
+	nextID := 1
+
+	for _, pb := range b.panelBuilders {
+		panel, err := pb.Build()
+		if err != nil {
+			return nil, fmt.Errorf("dashboard %q: %w", b.dash.Dashboard.Title, err)
+		}
+
+		if panel.ID == 0 {
+			for seenIDs[nextID] {
+				nextID++
+			}
+			panel.ID = nextID
+		}
+		if seenIDs[panel.ID] {
+			return nil, fmt.Errorf("dashboard %q: panel %q reuses id %d", b.dash.Dashboard.Title, panel.Title, panel.ID)
+		}
+		seenIDs[panel.ID] = true
+		nextID = panel.ID + 1
+
+		for _, existing := range panels {
+			if gridPosOverlaps(existing.GridPos, panel.GridPos) {
+				return nil, fmt.Errorf("dashboard %q: panel %q overlaps panel %q at grid (x=%d, y=%d)", b.dash.Dashboard.Title, panel.Title, existing.Title, panel.GridPos.X, panel.GridPos.Y)
+			}
+		}
+
+		panels = append(panels, *panel)
+	}
+
+	b.dash.Dashboard.Panels = panels
+	return b.dash, nil
+}
+
+// gridPosOverlaps reports whether two panels' grid rectangles intersect.
+func gridPosOverlaps(a, b GridPos) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+// PanelBuilder assembles a single Panel. Every setter returns the builder;
+// the first validation error encountered is returned by Build, not raised
+// immediately, so a chain reads the same whether or not a call fails.
+type PanelBuilder struct {
+	panel *Panel
+	err   error
+}
+
+// NewTimeseriesPanel starts a "timeseries" panel with the field-display
+// defaults this generator has always used (line, no fill stacking, no
+// points shown except on hover).
+func NewTimeseriesPanel(title string) *PanelBuilder {
+	return newPanelBuilder(title, "timeseries")
+}
+
+// NewStatPanel starts a "stat" panel (a single current-value tile).
+func NewStatPanel(title string) *PanelBuilder {
+	return newPanelBuilder(title, "stat")
+}
+
+func newPanelBuilder(title, panelType string) *PanelBuilder {
+	return &PanelBuilder{
+		panel: &Panel{
+			Title: title,
+			Type:  panelType,
+			FieldConfig: FieldConfig{
+				Defaults: Defaults{
+					Color:    Color{Mode: "palette-classic"},
+					Custom:   defaultCustom(),
+					Mappings: []interface{}{},
+					Thresholds: Thresholds{
+						Mode: "absolute",
+					},
+				},
+			},
+		},
+	}
+}
+
+func defaultCustom() Custom {
+	return Custom{
+		AxisPlacement:     "auto",
+		DrawStyle:         "line",
+		FillOpacity:       10,
+		GradientMode:      "none",
+		LineInterpolation: "linear",
+		LineWidth:         1,
+		PointSize:         5,
+		ScaleDistribution: ScaleDistribution{Type: "linear"},
+		ShowPoints:        "never",
+		Stacking:          Stacking{Group: "A", Mode: "none"},
+		ThresholdsStyle:   ThresholdsStyle{Mode: "off"},
+	}
+}
+
+// WithID pins the panel's id instead of letting Builder.Build assign the
+// next free one; useful when a dashboard's panels are edited in place and
+// existing ids (and any saved Grafana state keyed on them) should survive.
+func (p *PanelBuilder) WithID(id int) *PanelBuilder {
+	p.panel.ID = id
+	return p
+}
+
+// At places the panel on the dashboard grid.
+func (p *PanelBuilder) At(pos GridPos) *PanelBuilder {
+	p.panel.GridPos = pos
+	return p
+}
+
+// WithQuery appends a PromQL target, assigning the next refId (A, B, C, ...)
+// in the order queries are added.
+func (p *PanelBuilder) WithQuery(promql, legendFormat string) *PanelBuilder {
+	refID := string(rune('A' + len(p.panel.Targets)))
+	p.panel.Targets = append(p.panel.Targets, Target{
+		Expr:         promql,
+		LegendFormat: legendFormat,
+		RefID:        refID,
+	})
+	return p
+}
+
+// WithUnit sets the field's display unit, validated against validUnits.
+func (p *PanelBuilder) WithUnit(unit string) *PanelBuilder {
+	if !validUnits[unit] {
+		if p.err == nil {
+			p.err = fmt.Errorf("panel %q: unknown unit %q", p.panel.Title, unit)
+		}
+		return p
+	}
+	p.panel.FieldConfig.Defaults.Unit = unit
+	return p
+}
+
+// WithThresholds replaces the field's threshold steps (evaluated in the
+// order given, so they should be ascending by Value).
+func (p *PanelBuilder) WithThresholds(steps ...ThresholdStep) *PanelBuilder {
+	p.panel.FieldConfig.Defaults.Thresholds.Steps = steps
+	return p
+}
+
+// WithColorMode overrides the default "palette-classic" field color mode,
+// e.g. to "thresholds" for a stat panel colored by its threshold steps.
+func (p *PanelBuilder) WithColorMode(mode string) *PanelBuilder {
+	p.panel.FieldConfig.Defaults.Color = Color{Mode: mode}
+	return p
+}
+
+// WithOptions sets the panel-type-specific options block (e.g. a stat
+// panel's colorMode/graphMode/textMode), passed through as-is since it
+// varies per panel type and isn't part of the shared Grafana schema.
+func (p *PanelBuilder) WithOptions(options interface{}) *PanelBuilder {
+	p.panel.Options = options
+	return p
+}
+
+// Build validates and returns the finished panel.
+func (p *PanelBuilder) Build() (*Panel, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.panel.Title == "" {
+		return nil, fmt.Errorf("panel: title is required")
+	}
+	if len(p.panel.Targets) == 0 {
+		return nil, fmt.Errorf("panel %q: at least one WithQuery is required", p.panel.Title)
+	}
+	return p.panel, nil
+}
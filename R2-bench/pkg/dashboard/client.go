@@ -0,0 +1,193 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+// maxRetries/retryBaseDelay bound the exponential backoff GrafanaClient
+// applies to 429s, 5xxs, and transport or response-read errors; any other
+// 4xx is returned to the caller immediately since retrying won't help.
+const (
+	maxRetries     = 5
+	retryBaseDelay = 200 * time.Millisecond
+)
+
+// GrafanaClient talks to a Grafana instance's HTTP API to provision
+// dashboards, folders, and datasources, as an alternative to SaveDashboard
+// writing JSON for manual import.
+type GrafanaClient struct {
+	baseURL    string
+	apiKey     string
+	user, pass string
+	httpClient *http.Client
+}
+
+// NewGrafanaClient builds a client authenticating with an API key (a
+// Grafana service account token). baseURL should not have a trailing
+// slash, e.g. "https://grafana.example.com".
+func NewGrafanaClient(baseURL, apiKey string) *GrafanaClient {
+	return &GrafanaClient{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewGrafanaClientBasicAuth builds a client authenticating with HTTP basic
+// auth (a Grafana username/password) instead of an API key.
+func NewGrafanaClientBasicAuth(baseURL, user, pass string) *GrafanaClient {
+	return &GrafanaClient{
+		baseURL:    baseURL,
+		user:       user,
+		pass:       pass,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *GrafanaClient) authenticate(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	} else if c.user != "" {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+}
+
+// doWithRetry issues req, retrying with exponential backoff on 429 and 5xx
+// responses and on transport or response-read errors, up to maxRetries
+// times. The request body (if any) is captured
+// up front so it can be replayed on retry.
+func (c *GrafanaClient) doWithRetry(method, path string, body interface{}) ([]byte, int, error) {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, 0, fmt.Errorf("grafana client: marshal request body: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := retryBaseDelay * time.Duration(math.Pow(2, float64(attempt-1)))
+			time.Sleep(delay)
+		}
+
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+		if err != nil {
+			return nil, 0, fmt.Errorf("grafana client: build request: %w", err)
+		}
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		c.authenticate(req)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("grafana client: %s %s: %w", method, path, err)
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("grafana client: %s %s: read response: %w", method, path, err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("grafana client: %s %s: status %d: %s", method, path, resp.StatusCode, string(respBody))
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			return respBody, resp.StatusCode, fmt.Errorf("grafana client: %s %s: status %d: %s", method, path, resp.StatusCode, string(respBody))
+		}
+		return respBody, resp.StatusCode, nil
+	}
+	return nil, 0, fmt.Errorf("grafana client: giving up after %d retries: %w", maxRetries, lastErr)
+}
+
+// UploadDashboard uploads dash via POST /api/dashboards/db, filing it under
+// folderUID (empty string for the General folder) and overwriting any
+// existing dashboard with the same title.
+func (c *GrafanaClient) UploadDashboard(dash *GrafanaDashboard, folderUID string) error {
+	payload := struct {
+		Dashboard DashboardConfig `json:"dashboard"`
+		FolderUID string          `json:"folderUid,omitempty"`
+		Overwrite bool            `json:"overwrite"`
+	}{
+		Dashboard: dash.Dashboard,
+		FolderUID: folderUID,
+		Overwrite: true,
+	}
+	_, _, err := c.doWithRetry(http.MethodPost, "/api/dashboards/db", payload)
+	return err
+}
+
+// EnsureFolder looks up a folder by title, creating it via POST /api/folders
+// if it doesn't already exist, and returns its UID.
+func (c *GrafanaClient) EnsureFolder(title string) (string, error) {
+	body, _, err := c.doWithRetry(http.MethodGet, "/api/folders", nil)
+	if err != nil {
+		return "", err
+	}
+	var folders []struct {
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &folders); err != nil {
+		return "", fmt.Errorf("grafana client: parse folder list: %w", err)
+	}
+	for _, f := range folders {
+		if f.Title == title {
+			return f.UID, nil
+		}
+	}
+
+	body, _, err = c.doWithRetry(http.MethodPost, "/api/folders", struct {
+		Title string `json:"title"`
+	}{Title: title})
+	if err != nil {
+		return "", err
+	}
+	var created struct {
+		UID string `json:"uid"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return "", fmt.Errorf("grafana client: parse created folder: %w", err)
+	}
+	return created.UID, nil
+}
+
+// DatasourceConfig is the subset of Grafana's datasource API fields needed
+// to provision the Prometheus datasource the dashboard's queries target.
+type DatasourceConfig struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	URL       string            `json:"url"`
+	Access    string            `json:"access"`
+	UID       string            `json:"uid,omitempty"`
+	IsDefault bool              `json:"isDefault,omitempty"`
+	JSONData  map[string]string `json:"jsonData,omitempty"`
+}
+
+// EnsureDatasource provisions ds via POST /api/datasources. Grafana returns
+// a 409 if a datasource with the same name already exists; that's treated
+// as success rather than an error, since the intent is "make sure it
+// exists," not "create it exactly once."
+func (c *GrafanaClient) EnsureDatasource(ds DatasourceConfig) error {
+	_, status, err := c.doWithRetry(http.MethodPost, "/api/datasources", ds)
+	if err != nil && status == http.StatusConflict {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// provisioningYAML is the fixed shape of a Grafana file-provider config
+// (provisioning/dashboards/*.yaml); see
+// https://grafana.com/docs/grafana/latest/administration/provisioning/#dashboards.
+const provisioningYAML = `apiVersion: 1
+
+providers:
+  - name: %s
+    orgId: 1
+    folder: %s
+    type: file
+    disableDeletion: false
+    updateIntervalSeconds: 30
+    options:
+      path: %s
+`
+
+// WriteProvisioningBundle writes a Grafana file-based provisioning bundle
+// for dashboards under outputDir: each dashboard's JSON under
+// outputDir/dashboards/<name>.json, and a provider config at
+// outputDir/dashboards/r2-bench.yaml pointing at that directory and
+// folder, for operators who drop files into /etc/grafana/provisioning/
+// rather than calling the HTTP API.
+func WriteProvisioningBundle(dashboards map[string]*GrafanaDashboard, outputDir, folder string) error {
+	dashDir := filepath.Join(outputDir, "dashboards")
+	if err := os.MkdirAll(dashDir, 0755); err != nil {
+		return fmt.Errorf("provisioning bundle: create dashboards dir: %w", err)
+	}
+
+	for name, dash := range dashboards {
+		data, err := json.MarshalIndent(dash, "", "  ")
+		if err != nil {
+			return fmt.Errorf("provisioning bundle: marshal %q: %w", name, err)
+		}
+		path := filepath.Join(dashDir, name+".json")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("provisioning bundle: write %q: %w", path, err)
+		}
+	}
+
+	yamlPath := filepath.Join(dashDir, "r2-bench.yaml")
+	contents := fmt.Sprintf(provisioningYAML, "r2-bench", folder, dashDir)
+	if err := os.WriteFile(yamlPath, []byte(contents), 0644); err != nil {
+		return fmt.Errorf("provisioning bundle: write %q: %w", yamlPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Package dashboard is a small typed builder for Grafana dashboard JSON,
+// modeled after Grafana Cog's generated builders. It exists so callers
+// assemble a dashboard from NewDashboard/NewTimeseriesPanel/NewStatPanel
+// chains instead of writing out the nested Grafana schema literally, and so
+// a handful of structural mistakes (duplicate panel IDs, overlapping grid
+// positions, unknown units, an unsupported schemaVersion) are caught by
+// Build() instead of surfacing as a broken dashboard after import.
+package dashboard
+
+// GrafanaDashboard is the payload Grafana's dashboard import API and the
+// `dashboard` JSON file format both expect.
+type GrafanaDashboard struct {
+	Dashboard DashboardConfig `json:"dashboard"`
+	FolderID  int             `json:"folderId"`
+	Overwrite bool            `json:"overwrite"`
+}
+
+// DashboardConfig represents the dashboard configuration
+type DashboardConfig struct {
+	ID            interface{} `json:"id"`
+	Title         string      `json:"title"`
+	Tags          []string    `json:"tags"`
+	Style         string      `json:"style"`
+	Timezone      string      `json:"timezone"`
+	Panels        []Panel     `json:"panels"`
+	Time          TimeRange   `json:"time"`
+	Timepicker    Timepicker  `json:"timepicker"`
+	Templating    Templating  `json:"templating"`
+	Annotations   Annotations `json:"annotations"`
+	Refresh       string      `json:"refresh"`
+	SchemaVersion int         `json:"schemaVersion"`
+	Version       int         `json:"version"`
+	Links         []Link      `json:"links"`
+}
+
+// Panel represents a Grafana panel
+type Panel struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Type        string      `json:"type"`
+	GridPos     GridPos     `json:"gridPos"`
+	Targets     []Target    `json:"targets"`
+	FieldConfig FieldConfig `json:"fieldConfig"`
+	Options     interface{} `json:"options,omitempty"`
+}
+
+// GridPos represents panel grid position
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Target represents a query target
+type Target struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+	RefID        string `json:"refId"`
+}
+
+// FieldConfig represents field configuration
+type FieldConfig struct {
+	Defaults Defaults `json:"defaults"`
+}
+
+// Defaults represents default field settings
+type Defaults struct {
+	Color      Color         `json:"color"`
+	Custom     Custom        `json:"custom"`
+	Mappings   []interface{} `json:"mappings"`
+	Thresholds Thresholds    `json:"thresholds"`
+	Unit       string        `json:"unit"`
+}
+
+// Color represents color configuration
+type Color struct {
+	Mode string `json:"mode"`
+}
+
+// Custom represents custom field configuration
+type Custom struct {
+	AxisLabel         string            `json:"axisLabel"`
+	AxisPlacement     string            `json:"axisPlacement"`
+	BarAlignment      int               `json:"barAlignment"`
+	DrawStyle         string            `json:"drawStyle"`
+	FillOpacity       int               `json:"fillOpacity"`
+	GradientMode      string            `json:"gradientMode"`
+	HideFrom          HideFrom          `json:"hideFrom"`
+	LineInterpolation string            `json:"lineInterpolation"`
+	LineWidth         int               `json:"lineWidth"`
+	PointSize         int               `json:"pointSize"`
+	ScaleDistribution ScaleDistribution `json:"scaleDistribution"`
+	ShowPoints        string            `json:"showPoints"`
+	SpanNulls         bool              `json:"spanNulls"`
+	Stacking          Stacking          `json:"stacking"`
+	ThresholdsStyle   ThresholdsStyle   `json:"thresholdsStyle"`
+}
+
+// HideFrom represents hide configuration
+type HideFrom struct {
+	Legend  bool `json:"legend"`
+	Tooltip bool `json:"tooltip"`
+	Viz     bool `json:"viz"`
+}
+
+// ScaleDistribution represents scale distribution
+type ScaleDistribution struct {
+	Type string `json:"type"`
+}
+
+// Stacking represents stacking configuration
+type Stacking struct {
+	Group string `json:"group"`
+	Mode  string `json:"mode"`
+}
+
+// ThresholdsStyle represents thresholds style
+type ThresholdsStyle struct {
+	Mode string `json:"mode"`
+}
+
+// Thresholds represents thresholds configuration
+type Thresholds struct {
+	Mode  string          `json:"mode"`
+	Steps []ThresholdStep `json:"steps"`
+}
+
+// ThresholdStep represents a threshold step
+type ThresholdStep struct {
+	Color string  `json:"color"`
+	Value float64 `json:"value"`
+}
+
+// TimeRange represents time range
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Timepicker represents timepicker configuration
+type Timepicker struct {
+	RefreshIntervals []string `json:"refresh_intervals"`
+}
+
+// Templating represents templating configuration
+type Templating struct {
+	List []interface{} `json:"list"`
+}
+
+// TemplateVariable is a Grafana dashboard template variable, appended to
+// Templating.List by Builder.WithVariable, WithQueryVariable, or
+// WithIntervalVariable.
+type TemplateVariable struct {
+	Name    string           `json:"name"`
+	Label   string           `json:"label,omitempty"`
+	Type    string           `json:"type"`
+	Query   string           `json:"query,omitempty"`
+	Refresh int              `json:"refresh,omitempty"`
+	Multi   bool             `json:"multi,omitempty"`
+	Options []VariableOption `json:"options,omitempty"`
+	Current VariableOption   `json:"current,omitempty"`
+}
+
+// VariableOption is one selectable value of a "custom" or "interval"
+// template variable.
+type VariableOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// Annotations represents annotations configuration
+type Annotations struct {
+	List []interface{} `json:"list"`
+}
+
+// Link represents a dashboard link
+type Link struct {
+	AsDropdown  bool     `json:"asDropdown"`
+	Icon        string   `json:"icon"`
+	IncludeVars bool     `json:"includeVars"`
+	Tags        []string `json:"tags"`
+	TargetBlank bool     `json:"targetBlank"`
+	Title       string   `json:"title"`
+	Tooltip     string   `json:"tooltip"`
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+}
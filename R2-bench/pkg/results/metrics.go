@@ -0,0 +1,45 @@
+package results
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are registered once at package init (rather than per Writer) so
+// creating one ParquetWriter per endpoint, as BenchmarkRunner does, doesn't
+// panic on duplicate registration; each Writer selects its own series via
+// WithLabelValues(runID, instanceType).
+var (
+	bytesWrittenTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "r2_bench_parquet_bytes_written_total",
+			Help: "Total bytes flushed to Parquet result files",
+		},
+		[]string{"run_id", "instance_type"},
+	)
+	rowsWrittenTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "r2_bench_parquet_rows_written_total",
+			Help: "Total rows flushed to Parquet result files",
+		},
+		[]string{"run_id", "instance_type"},
+	)
+	flushLatencyMs = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "r2_bench_parquet_flush_latency_ms",
+			Help:    "Latency of each row-group flush to disk, in milliseconds",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14), // 1ms to ~8s
+		},
+		[]string{"run_id", "instance_type"},
+	)
+	rotationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "r2_bench_parquet_rotations_total",
+			Help: "Total number of Parquet file rotations (rotate interval reached)",
+		},
+		[]string{"run_id", "instance_type"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(bytesWrittenTotal, rowsWrittenTotal, flushLatencyMs, rotationsTotal)
+}
@@ -0,0 +1,80 @@
+package results
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// readBatchSize is how many rows OpenReader's Reader pulls from disk per
+// underlying Read call.
+const readBatchSize = 256
+
+// Reader streams Records back out of a Parquet file written by
+// ParquetWriter, for offline analysis or the parquet-cat CLI.
+type Reader struct {
+	file      *local.LocalFile
+	reader    *reader.ParquetReader
+	totalRows int64
+	readRows  int64
+	buf       []Record
+	bufPos    int
+}
+
+// OpenReader opens path for reading.
+func OpenReader(path string) (*Reader, error) {
+	pf, err := local.NewLocalFileReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("results: open %s: %w", path, err)
+	}
+	file := pf.(*local.LocalFile)
+
+	pr, err := reader.NewParquetReader(file, new(Record), int64(readBatchSize))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("results: open parquet reader for %s: %w", path, err)
+	}
+
+	return &Reader{file: file, reader: pr, totalRows: pr.GetNumRows()}, nil
+}
+
+// Next returns the next Record, or io.EOF once every row has been read.
+func (r *Reader) Next() (Record, error) {
+	if r.bufPos >= len(r.buf) {
+		if err := r.fill(); err != nil {
+			return Record{}, err
+		}
+	}
+	rec := r.buf[r.bufPos]
+	r.bufPos++
+	return rec, nil
+}
+
+func (r *Reader) fill() error {
+	if r.readRows >= r.totalRows {
+		return io.EOF
+	}
+
+	want := int64(readBatchSize)
+	if remaining := r.totalRows - r.readRows; remaining < want {
+		want = remaining
+	}
+
+	rows := make([]Record, want)
+	if err := r.reader.Read(&rows); err != nil {
+		return fmt.Errorf("results: read rows: %w", err)
+	}
+
+	r.readRows += int64(len(rows))
+	r.buf = rows
+	r.bufPos = 0
+	return nil
+}
+
+// Close closes the underlying file.
+func (r *Reader) Close() error {
+	r.reader.ReadStop()
+	return r.file.Close()
+}
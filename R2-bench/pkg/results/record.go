@@ -0,0 +1,40 @@
+// Package results provides the on-disk analytics format for benchmark
+// request results: a rotating, compressed Parquet writer fed by a channel,
+// and a reader for offline analysis (see the parquet-cat CLI).
+package results
+
+import "time"
+
+// Record mirrors main.RequestResult's fields and parquet tags. It is
+// defined here rather than imported because a `main` package cannot be
+// imported by another package; callers convert their own result type to a
+// Record at the Ingest boundary (see toRecord in the caller).
+type Record struct {
+	Timestamp           time.Time `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	ThreadID            int       `parquet:"name=thread_id, type=INT32"`
+	ConnID              int       `parquet:"name=conn_id, type=INT32"`
+	ObjectKey           string    `parquet:"name=object_key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RangeStart          int64     `parquet:"name=range_start, type=INT64"`
+	RangeLen            int64     `parquet:"name=range_len, type=INT64"`
+	// PartNumber is 0 for a single whole-range request, and the 1-based
+	// part index when the download manager split the range across
+	// concurrent sub-range reads (see pkg/download).
+	PartNumber          int       `parquet:"name=part_number, type=INT32"`
+	Bytes               int64     `parquet:"name=bytes, type=INT64"`
+	LatencyMs           float64   `parquet:"name=latency_ms, type=DOUBLE"`
+	HTTPStatus          int       `parquet:"name=http_status, type=INT32"`
+	RetryCount          int       `parquet:"name=retry_count, type=INT32"`
+	ErrMsg              string    `parquet:"name=err_msg, type=BYTE_ARRAY, convertedtype=UTF8"`
+	InstanceType        string    `parquet:"name=instance_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Concurrency         int       `parquet:"name=concurrency, type=INT32"`
+	RTTUs               int64     `parquet:"name=rtt_us, type=INT64"`
+	TCPRetx             int       `parquet:"name=tcp_retx, type=INT32"`
+	LinkUtilPct         float64   `parquet:"name=link_util_pct, type=DOUBLE"`
+	WorkloadType        string    `parquet:"name=workload_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	WorkloadParams      string    `parquet:"name=workload_params, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScheduledAt         time.Time `parquet:"name=scheduled_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	CoordinatedOmission bool      `parquet:"name=coordinated_omission, type=BOOLEAN"`
+	Endpoint            string    `parquet:"name=endpoint, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RequestID           string    `parquet:"name=request_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RunID               string    `parquet:"name=run_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
@@ -0,0 +1,246 @@
+package results
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Compression selects the Parquet compression codec a ParquetWriter uses.
+type Compression string
+
+const (
+	CompressionSnappy Compression = "snappy"
+	CompressionZstd   Compression = "zstd"
+	CompressionNone   Compression = "none"
+)
+
+func (c Compression) codec() (parquet.CompressionCodec, error) {
+	switch c {
+	case "", CompressionSnappy:
+		return parquet.CompressionCodec_SNAPPY, nil
+	case CompressionZstd:
+		return parquet.CompressionCodec_ZSTD, nil
+	case CompressionNone:
+		return parquet.CompressionCodec_UNCOMPRESSED, nil
+	default:
+		return 0, fmt.Errorf("results: unknown compression %q", c)
+	}
+}
+
+// defaults: flush every 1000 rows (the batch size storage.ParquetWriter
+// used before this package existed) and rotate hourly.
+const (
+	defaultMaxRows        = 1000
+	defaultMaxBytes       = 64 * 1024 * 1024
+	defaultRotateInterval = 1 * time.Hour
+	defaultParallelism    = 4
+)
+
+// WriterConfig configures a ParquetWriter's output location, row-group
+// flush thresholds, rotation interval, and compression.
+type WriterConfig struct {
+	// BaseDir is the root results directory; files are written under
+	// BaseDir/<RunID>/<InstanceType>/<start_ts>.parquet.
+	BaseDir      string
+	RunID        string
+	InstanceType string
+
+	// MaxRows/MaxBytes trigger a row-group flush once either is reached
+	// (0 uses the package default).
+	MaxRows  int
+	MaxBytes int64
+
+	// RotateInterval closes the current file and opens a new one once
+	// it's been open this long (0 uses the package default).
+	RotateInterval time.Duration
+
+	Compression Compression
+}
+
+func (c WriterConfig) withDefaults() WriterConfig {
+	if c.MaxRows <= 0 {
+		c.MaxRows = defaultMaxRows
+	}
+	if c.MaxBytes <= 0 {
+		c.MaxBytes = defaultMaxBytes
+	}
+	if c.RotateInterval <= 0 {
+		c.RotateInterval = defaultRotateInterval
+	}
+	return c
+}
+
+// ParquetWriter buffers Records, flushing a row group to disk once
+// MaxRows or MaxBytes is reached, and rotating to a new file once
+// RotateInterval has elapsed. It is safe for concurrent use, though the
+// intended access pattern is a single Ingest goroutine per writer.
+type ParquetWriter struct {
+	cfg WriterConfig
+
+	mu          sync.Mutex
+	file        *local.LocalFile
+	writer      *writer.ParquetWriter
+	currentPath string
+	openedAt    time.Time
+	pendingRows int
+	pendingSize int64
+}
+
+// NewParquetWriter creates a ParquetWriter and opens its first output file.
+func NewParquetWriter(cfg WriterConfig) (*ParquetWriter, error) {
+	cfg = cfg.withDefaults()
+	if _, err := cfg.Compression.codec(); err != nil {
+		return nil, err
+	}
+
+	pw := &ParquetWriter{cfg: cfg}
+	if err := pw.openLocked(); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *ParquetWriter) openLocked() error {
+	dir := filepath.Join(pw.cfg.BaseDir, pw.cfg.RunID, pw.cfg.InstanceType)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("results: create output directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.parquet", time.Now().UnixNano()))
+	pf, err := local.NewLocalFileWriter(path)
+	if err != nil {
+		return fmt.Errorf("results: create parquet file: %w", err)
+	}
+	file := pf.(*local.LocalFile)
+
+	w, err := writer.NewParquetWriter(file, new(Record), defaultParallelism)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("results: create parquet writer: %w", err)
+	}
+	codec, _ := pw.cfg.Compression.codec()
+	w.CompressionType = codec
+
+	pw.file = file
+	pw.writer = w
+	pw.currentPath = path
+	pw.openedAt = time.Now()
+	pw.pendingRows = 0
+	pw.pendingSize = 0
+	return nil
+}
+
+// recordSize estimates a Record's on-disk footprint for the MaxBytes
+// threshold; exact sizing isn't necessary, only a rough per-row budget.
+func recordSize(r Record) int64 {
+	return int64(len(r.ObjectKey) + len(r.ErrMsg) + len(r.WorkloadType) + len(r.WorkloadParams) + len(r.Endpoint) + len(r.RequestID) + len(r.RunID) + 128)
+}
+
+// Ingest drains records, writing each to the current Parquet file and
+// flushing/rotating as thresholds are reached. It returns when records is
+// closed (after a final flush and Close) or ctx is done, and returns the
+// first write error encountered, if any. Backpressure comes from records
+// being an unbuffered or small-buffered channel: a slow disk simply stalls
+// the sender.
+func (pw *ParquetWriter) Ingest(ctx context.Context, records <-chan Record) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return pw.Close()
+		case r, ok := <-records:
+			if !ok {
+				return pw.Close()
+			}
+			if err := pw.write(r); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (pw *ParquetWriter) write(r Record) error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	if err := pw.writer.Write(r); err != nil {
+		return fmt.Errorf("results: write record: %w", err)
+	}
+	pw.pendingRows++
+	pw.pendingSize += recordSize(r)
+
+	if pw.pendingRows >= pw.cfg.MaxRows || pw.pendingSize >= pw.cfg.MaxBytes {
+		if err := pw.flushLocked(); err != nil {
+			return err
+		}
+	}
+	if time.Since(pw.openedAt) >= pw.cfg.RotateInterval {
+		if err := pw.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pw *ParquetWriter) flushLocked() error {
+	start := time.Now()
+	offsetBefore, _ := pw.file.File.Seek(0, io.SeekCurrent)
+	if err := pw.writer.Flush(true); err != nil {
+		return fmt.Errorf("results: flush row group: %w", err)
+	}
+	offsetAfter, _ := pw.file.File.Seek(0, io.SeekCurrent)
+	flushLatencyMs.WithLabelValues(pw.cfg.RunID, pw.cfg.InstanceType).Observe(float64(time.Since(start).Microseconds()) / 1000.0)
+	rowsWrittenTotal.WithLabelValues(pw.cfg.RunID, pw.cfg.InstanceType).Add(float64(pw.pendingRows))
+	bytesWrittenTotal.WithLabelValues(pw.cfg.RunID, pw.cfg.InstanceType).Add(float64(offsetAfter - offsetBefore))
+	pw.pendingRows = 0
+	pw.pendingSize = 0
+	return nil
+}
+
+func (pw *ParquetWriter) rotateLocked() error {
+	if err := pw.closeCurrentLocked(); err != nil {
+		return err
+	}
+	rotationsTotal.WithLabelValues(pw.cfg.RunID, pw.cfg.InstanceType).Inc()
+	return pw.openLocked()
+}
+
+func (pw *ParquetWriter) closeCurrentLocked() error {
+	if pw.pendingRows > 0 {
+		if err := pw.flushLocked(); err != nil {
+			return err
+		}
+	}
+	if err := pw.writer.WriteStop(); err != nil {
+		return fmt.Errorf("results: stop parquet writer: %w", err)
+	}
+	if err := pw.file.File.Sync(); err != nil {
+		return fmt.Errorf("results: fsync %s: %w", pw.currentPath, err)
+	}
+	if err := pw.file.Close(); err != nil {
+		return fmt.Errorf("results: close %s: %w", pw.currentPath, err)
+	}
+	return nil
+}
+
+// Close flushes any buffered rows, fsyncs, and closes the current file.
+func (pw *ParquetWriter) Close() error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	return pw.closeCurrentLocked()
+}
+
+// CurrentPath returns the path of the file currently being written.
+func (pw *ParquetWriter) CurrentPath() string {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	return pw.currentPath
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// requestMode selects whether workers issue requests back-to-back (closed
+// loop) or are paced to a target rate independent of concurrency (open
+// loop).
+type requestMode string
+
+const (
+	modeClosed requestMode = "closed"
+	modeOpen   requestMode = "open"
+)
+
+// scheduledRequest is a unit of open-loop work: the time it was meant to be
+// sent, queued for a worker to pick up and execute.
+type scheduledRequest struct {
+	scheduledAt time.Time
+}
+
+// runOpenLoopDispatcher paces scheduled requests onto pending at the
+// configured target RPS until endTime. When pending is full, the request is
+// never enqueued and is reported as a coordinated-omission error so latency
+// histograms downstream can be corrected instead of silently under-counting
+// queueing delay.
+func (br *BenchmarkRunner) runOpenLoopDispatcher(ctx context.Context, ep *Endpoint, pending chan<- scheduledRequest, endTime time.Time) {
+	if br.targetRPSLimiter == nil {
+		return
+	}
+
+	for time.Now().Before(endTime) {
+		if err := br.targetRPSLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		req := scheduledRequest{scheduledAt: time.Now()}
+		select {
+		case pending <- req:
+		default:
+			br.recordCoordinatedOmission(ep, req)
+		}
+	}
+}
+
+// recordCoordinatedOmission reports a request that the open-loop dispatcher
+// could not hand to a worker because the pending queue was full, so the
+// measured run doesn't silently hide the resulting queueing delay.
+func (br *BenchmarkRunner) recordCoordinatedOmission(ep *Endpoint, req scheduledRequest) {
+	result := RequestResult{
+		Timestamp:           time.Now(),
+		ScheduledAt:         req.scheduledAt,
+		InstanceType:        br.config.InstanceType,
+		ErrMsg:              "coordinated omission: pending queue full",
+		HTTPStatus:          503,
+		CoordinatedOmission: true,
+		Endpoint:            ep.Label,
+	}
+	select {
+	case br.results <- result:
+	default:
+	}
+}
+
+// applySafetyLimits blocks the caller, if configured, until the global
+// requests-per-second and bytes-per-second token buckets have capacity,
+// protecting production buckets from being tripped into throttling.
+// maxRPSLimiter is read under limiterMu since SetMaxRPS can swap it in from
+// the admin API while requests are in flight, and maxBytesLimiter's burst
+// can be resized by SetRangeSize for the same reason. length is clamped to
+// the limiter's current burst so a still-in-flight request sized against a
+// stale range-size never exceeds WaitN's maximum and errors out.
+func (br *BenchmarkRunner) applySafetyLimits(ctx context.Context, length int64) error {
+	br.limiterMu.RLock()
+	rpsLimiter := br.maxRPSLimiter
+	bytesLimiter := br.maxBytesLimiter
+	br.limiterMu.RUnlock()
+
+	if rpsLimiter != nil {
+		if err := rpsLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if bytesLimiter != nil {
+		n := int(length)
+		if burst := bytesLimiter.Burst(); n > burst {
+			n = burst
+		}
+		if err := bytesLimiter.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newLimiter returns nil when ratePerSec is non-positive, meaning "no
+// limit", matching how the rest of the config treats zero-value flags.
+func newLimiter(ratePerSec float64, burst int) *rate.Limiter {
+	if ratePerSec <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(ratePerSec), burst)
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	smithy "github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"r2-bench/instances/objstore"
+)
+
+// BucketMetricsOptions configures NewBucketMetrics.
+type BucketMetricsOptions struct {
+	// NativeHistograms switches the per-operation latency histogram to
+	// Prometheus native (sparse) histograms instead of classic
+	// ExponentialBuckets(1, 2, 20), which gives much finer resolution
+	// around p50/p99 without having to hand-pick bucket boundaries.
+	NativeHistograms bool
+}
+
+// BucketMetrics holds the Prometheus collectors shared by every
+// InstrumentedBucket wrapping a driver in the same process. Construct one
+// with NewBucketMetrics and pass it to NewInstrumentedBucket for each
+// endpoint's driver.
+type BucketMetrics struct {
+	opLatency     *prometheus.HistogramVec
+	bytesFetched  *prometheus.CounterVec
+	bytesUploaded *prometheus.CounterVec
+	inFlight      *prometheus.GaugeVec
+	opErrors      *prometheus.CounterVec
+}
+
+// NewBucketMetrics creates and registers the collectors InstrumentedBucket
+// records into, following the Thanos/Arvados volumeMetricsVecs pattern of
+// instrumenting the storage interface itself rather than requiring every
+// caller to remember to record its own metrics.
+func NewBucketMetrics(opts BucketMetricsOptions) *BucketMetrics {
+	histOpts := prometheus.HistogramOpts{
+		Name: "r2_bench_bucket_op_latency_ms",
+		Help: "Storage driver operation latency in milliseconds, by operation",
+	}
+	if opts.NativeHistograms {
+		histOpts.NativeHistogramBucketFactor = 1.1
+		histOpts.NativeHistogramMaxBucketNumber = 160
+		histOpts.NativeHistogramMinResetDuration = time.Hour
+	} else {
+		histOpts.Buckets = prometheus.ExponentialBuckets(1, 2, 20) // 1ms to ~524s
+	}
+
+	m := &BucketMetrics{
+		opLatency: prometheus.NewHistogramVec(histOpts, []string{"run_id", "instance_type", "endpoint", "operation"}),
+		bytesFetched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "r2_bench_bucket_bytes_fetched_total",
+			Help: "Total bytes returned by GetObjectRange",
+		}, []string{"run_id", "instance_type", "endpoint"}),
+		bytesUploaded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "r2_bench_bucket_bytes_uploaded_total",
+			Help: "Total bytes sent by UploadObject",
+		}, []string{"run_id", "instance_type", "endpoint"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "r2_bench_bucket_in_flight_operations",
+			Help: "Number of storage driver operations currently in flight, by operation",
+		}, []string{"run_id", "instance_type", "endpoint", "operation"}),
+		opErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "r2_bench_bucket_errors_total",
+			Help: "Total storage driver errors, by operation and error code",
+		}, []string{"run_id", "instance_type", "endpoint", "operation", "error_code"}),
+	}
+
+	prometheus.MustRegister(m.opLatency, m.bytesFetched, m.bytesUploaded, m.inFlight, m.opErrors)
+	return m
+}
+
+// InstrumentedBucket wraps an objstore.Bucket so every GetObjectRange,
+// UploadObject, ObjectExists and GetObjectSize call records a
+// per-operation latency observation, an in-flight gauge, byte counters for
+// range-get and upload, and an error counter split by the AWS/R2 API error
+// code (SlowDown, ServiceUnavailable, RequestTimeout, ...), without the
+// caller having to remember to record anything itself. The multipart
+// upload methods pass straight through to the wrapped driver and are not
+// recorded.
+type InstrumentedBucket struct {
+	objstore.Bucket
+	metrics *BucketMetrics
+	labels  []string // run_id, instance_type, endpoint, in that order
+}
+
+// NewInstrumentedBucket wraps bucket so every GetObjectRange/UploadObject/
+// ObjectExists/GetObjectSize call is recorded into metrics under the given
+// run_id/instance_type/endpoint labels.
+func NewInstrumentedBucket(bucket objstore.Bucket, metrics *BucketMetrics, runID, instanceType, endpoint string) *InstrumentedBucket {
+	return &InstrumentedBucket{Bucket: bucket, metrics: metrics, labels: []string{runID, instanceType, endpoint}}
+}
+
+// begin marks operation as in-flight and returns its start time.
+func (b *InstrumentedBucket) begin(operation string) time.Time {
+	b.metrics.inFlight.WithLabelValues(append(append([]string{}, b.labels...), operation)...).Inc()
+	return time.Now()
+}
+
+// end records operation's latency and, if it failed, its error code, and
+// clears the in-flight gauge raised by begin.
+func (b *InstrumentedBucket) end(operation string, start time.Time, err error) {
+	opLabels := append(append([]string{}, b.labels...), operation)
+	b.metrics.opLatency.WithLabelValues(opLabels...).Observe(float64(time.Since(start).Microseconds()) / 1000.0)
+	b.metrics.inFlight.WithLabelValues(opLabels...).Dec()
+	if err != nil {
+		b.metrics.opErrors.WithLabelValues(append(opLabels, errorCode(err))...).Inc()
+	}
+}
+
+func (b *InstrumentedBucket) GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error) {
+	startTime := b.begin("get_range")
+	data, err := b.Bucket.GetObjectRange(ctx, objectKey, start, length)
+	b.end("get_range", startTime, err)
+	if err == nil {
+		b.metrics.bytesFetched.WithLabelValues(b.labels...).Add(float64(len(data)))
+	}
+	return data, err
+}
+
+func (b *InstrumentedBucket) UploadObject(ctx context.Context, objectKey string, data []byte) error {
+	startTime := b.begin("put")
+	err := b.Bucket.UploadObject(ctx, objectKey, data)
+	b.end("put", startTime, err)
+	if err == nil {
+		b.metrics.bytesUploaded.WithLabelValues(b.labels...).Add(float64(len(data)))
+	}
+	return err
+}
+
+func (b *InstrumentedBucket) ObjectExists(ctx context.Context, objectKey string) (bool, error) {
+	startTime := b.begin("head")
+	exists, err := b.Bucket.ObjectExists(ctx, objectKey)
+	b.end("head", startTime, err)
+	return exists, err
+}
+
+func (b *InstrumentedBucket) GetObjectSize(ctx context.Context, objectKey string) (int64, error) {
+	startTime := b.begin("head")
+	size, err := b.Bucket.GetObjectSize(ctx, objectKey)
+	b.end("head", startTime, err)
+	return size, err
+}
+
+// errorCode classifies err into a low-cardinality label: the AWS/R2 API
+// error code (SlowDown, ServiceUnavailable, RequestTimeout, ...) when the
+// SDK returned one via the smithy APIError interface, one of a few common
+// transport failure modes otherwise, or "other".
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "connection reset"):
+		return "connection_reset"
+	case strings.Contains(msg, "timeout"), strings.Contains(msg, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(msg, "EOF"):
+		return "eof"
+	default:
+		return "other"
+	}
+}
@@ -13,13 +13,15 @@ import (
 // PrometheusExporter handles Prometheus metrics collection and serving
 type PrometheusExporter struct {
 	// Metrics
-	throughputGauge    *prometheus.GaugeVec
-	latencyHistogram   *prometheus.HistogramVec
-	qpsCounter         *prometheus.CounterVec
-	errorCounter       *prometheus.CounterVec
-	concurrencyGauge   *prometheus.GaugeVec
-	networkGauge       *prometheus.GaugeVec
-	cpuGauge           *prometheus.GaugeVec
+	throughputGauge        *prometheus.GaugeVec
+	latencyHistogram       *prometheus.HistogramVec
+	qpsCounter             *prometheus.CounterVec
+	errorCounter           *prometheus.CounterVec
+	concurrencyGauge       *prometheus.GaugeVec
+	targetConcurrencyGauge *prometheus.GaugeVec
+	networkGauge           *prometheus.GaugeVec
+	cpuGauge               *prometheus.GaugeVec
+	configReloadsCounter   *prometheus.CounterVec
 
 	// Internal state
 	mutex     sync.RWMutex
@@ -27,7 +29,11 @@ type PrometheusExporter struct {
 	lastError float64
 }
 
-// NewPrometheusExporter creates a new Prometheus exporter
+// NewPrometheusExporter creates a new Prometheus exporter. Every per-run
+// vector carries `run_id` and `instance_type` labels so a single process
+// comparing several storage backends (e.g. R2 vs S3), or a dashboard
+// comparing several runs, produces side-by-side series rather than
+// overwriting each other.
 func NewPrometheusExporter() *PrometheusExporter {
 	exporter := &PrometheusExporter{
 		throughputGauge: prometheus.NewGaugeVec(
@@ -35,7 +41,7 @@ func NewPrometheusExporter() *PrometheusExporter {
 				Name: "r2_bench_throughput_mbps",
 				Help: "Current throughput in Mbps",
 			},
-			[]string{"instance_type", "concurrency"},
+			[]string{"run_id", "instance_type", "concurrency", "endpoint"},
 		),
 		latencyHistogram: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
@@ -43,42 +49,56 @@ func NewPrometheusExporter() *PrometheusExporter {
 				Help:    "Request latency in milliseconds",
 				Buckets: prometheus.ExponentialBuckets(1, 2, 20), // 1ms to ~1s
 			},
-			[]string{"instance_type", "concurrency"},
+			[]string{"run_id", "instance_type", "concurrency", "endpoint"},
 		),
 		qpsCounter: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "r2_bench_requests_total",
 				Help: "Total number of requests",
 			},
-			[]string{"instance_type", "concurrency", "status"},
+			[]string{"run_id", "instance_type", "concurrency", "status", "endpoint"},
 		),
 		errorCounter: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "r2_bench_errors_total",
 				Help: "Total number of errors",
 			},
-			[]string{"instance_type", "concurrency", "error_type"},
+			[]string{"run_id", "instance_type", "concurrency", "error_type", "endpoint"},
 		),
 		concurrencyGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "r2_bench_concurrency",
 				Help: "Current concurrency level",
 			},
-			[]string{"instance_type"},
+			[]string{"run_id", "instance_type", "endpoint"},
+		),
+		targetConcurrencyGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "r2_bench_target_concurrency",
+				Help: "Concurrency level currently selected by the adaptive search controller",
+			},
+			[]string{"run_id", "instance_type", "endpoint"},
 		),
 		networkGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "r2_bench_network_utilization",
 				Help: "Network utilization percentage",
 			},
-			[]string{"instance_type", "metric"},
+			[]string{"run_id", "instance_type", "metric", "endpoint"},
 		),
 		cpuGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "r2_bench_cpu_utilization",
 				Help: "CPU utilization percentage",
 			},
-			[]string{"instance_type"},
+			[]string{"run_id", "instance_type", "endpoint"},
+		),
+		configReloadsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "r2_bench_config_reloads_total",
+				Help: "Total number of -config file reload attempts",
+			},
+			[]string{"result"},
 		),
 	}
 
@@ -89,8 +109,10 @@ func NewPrometheusExporter() *PrometheusExporter {
 		exporter.qpsCounter,
 		exporter.errorCounter,
 		exporter.concurrencyGauge,
+		exporter.targetConcurrencyGauge,
 		exporter.networkGauge,
 		exporter.cpuGauge,
+		exporter.configReloadsCounter,
 	)
 
 	return exporter
@@ -103,44 +125,56 @@ func (pe *PrometheusExporter) StartServer(addr string) error {
 }
 
 // RecordRequest records a single request result
-func (pe *PrometheusExporter) RecordRequest(instanceType string, concurrency int, latencyMs float64, status int, errMsg string) {
+func (pe *PrometheusExporter) RecordRequest(runID, instanceType string, concurrency int, latencyMs float64, status int, errMsg string, endpoint string) {
 	pe.mutex.Lock()
 	defer pe.mutex.Unlock()
 
 	// Record latency
-	pe.latencyHistogram.WithLabelValues(instanceType, fmt.Sprintf("%d", concurrency)).Observe(latencyMs)
+	pe.latencyHistogram.WithLabelValues(runID, instanceType, fmt.Sprintf("%d", concurrency), endpoint).Observe(latencyMs)
 
 	// Record request count
 	statusStr := fmt.Sprintf("%d", status)
-	pe.qpsCounter.WithLabelValues(instanceType, fmt.Sprintf("%d", concurrency), statusStr).Inc()
+	pe.qpsCounter.WithLabelValues(runID, instanceType, fmt.Sprintf("%d", concurrency), statusStr, endpoint).Inc()
 
 	// Record errors
 	if errMsg != "" {
-		pe.errorCounter.WithLabelValues(instanceType, fmt.Sprintf("%d", concurrency), "request_error").Inc()
+		pe.errorCounter.WithLabelValues(runID, instanceType, fmt.Sprintf("%d", concurrency), "request_error", endpoint).Inc()
 	} else if status >= 400 {
-		pe.errorCounter.WithLabelValues(instanceType, fmt.Sprintf("%d", concurrency), "http_error").Inc()
+		pe.errorCounter.WithLabelValues(runID, instanceType, fmt.Sprintf("%d", concurrency), "http_error", endpoint).Inc()
 	}
 }
 
 // UpdateThroughput updates the throughput metric
-func (pe *PrometheusExporter) UpdateThroughput(instanceType string, concurrency int, throughputMbps float64) {
-	pe.throughputGauge.WithLabelValues(instanceType, fmt.Sprintf("%d", concurrency)).Set(throughputMbps)
+func (pe *PrometheusExporter) UpdateThroughput(runID, instanceType string, concurrency int, throughputMbps float64, endpoint string) {
+	pe.throughputGauge.WithLabelValues(runID, instanceType, fmt.Sprintf("%d", concurrency), endpoint).Set(throughputMbps)
 }
 
 // UpdateConcurrency updates the concurrency metric
-func (pe *PrometheusExporter) UpdateConcurrency(instanceType string, concurrency int) {
-	pe.concurrencyGauge.WithLabelValues(instanceType).Set(float64(concurrency))
+func (pe *PrometheusExporter) UpdateConcurrency(runID, instanceType string, concurrency int, endpoint string) {
+	pe.concurrencyGauge.WithLabelValues(runID, instanceType, endpoint).Set(float64(concurrency))
+}
+
+// UpdateTargetConcurrency updates the concurrency level currently selected
+// by the adaptive search controller.
+func (pe *PrometheusExporter) UpdateTargetConcurrency(runID, instanceType string, concurrency int, endpoint string) {
+	pe.targetConcurrencyGauge.WithLabelValues(runID, instanceType, endpoint).Set(float64(concurrency))
 }
 
 // UpdateNetworkStats updates network-related metrics
-func (pe *PrometheusExporter) UpdateNetworkStats(instanceType string, linkUtilPct, tcpRetx float64) {
-	pe.networkGauge.WithLabelValues(instanceType, "link_utilization").Set(linkUtilPct)
-	pe.networkGauge.WithLabelValues(instanceType, "tcp_retransmits").Set(tcpRetx)
+func (pe *PrometheusExporter) UpdateNetworkStats(runID, instanceType string, linkUtilPct, tcpRetx float64, endpoint string) {
+	pe.networkGauge.WithLabelValues(runID, instanceType, "link_utilization", endpoint).Set(linkUtilPct)
+	pe.networkGauge.WithLabelValues(runID, instanceType, "tcp_retransmits", endpoint).Set(tcpRetx)
 }
 
 // UpdateCPUStats updates CPU-related metrics
-func (pe *PrometheusExporter) UpdateCPUStats(instanceType string, cpuUtilization float64) {
-	pe.cpuGauge.WithLabelValues(instanceType).Set(cpuUtilization)
+func (pe *PrometheusExporter) UpdateCPUStats(runID, instanceType string, cpuUtilization float64, endpoint string) {
+	pe.cpuGauge.WithLabelValues(runID, instanceType, endpoint).Set(cpuUtilization)
+}
+
+// RecordConfigReload counts a -config file reload attempt, result being
+// "success" or "error".
+func (pe *PrometheusExporter) RecordConfigReload(result string) {
+	pe.configReloadsCounter.WithLabelValues(result).Inc()
 }
 
 // GetMetrics returns current metric values for monitoring
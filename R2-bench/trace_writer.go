@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// WriteTrace persists a ConcurrencyController's search trace to Parquet so
+// an adaptive-concurrency run can be reproduced and its decisions audited
+// after the fact. It writes the full search trace as a single Parquet file under
+// outputDir, one row per evaluated concurrency step.
+//
+// This lives in package main, not package storage, because ConcurrencyStep
+// does: a main package can't be imported by another package, so a
+// storage.WriteTrace(steps []main.ConcurrencyStep) as this used to be
+// declared doesn't compile.
+func WriteTrace(outputDir string, steps []ConcurrencyStep) (string, error) {
+	timestamp := time.Now().Format("20060102-150405")
+	fileName := fmt.Sprintf("concurrency-trace-%s.parquet", timestamp)
+	filePath := filepath.Join(outputDir, fileName)
+
+	file, err := local.NewLocalFileWriter(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create trace file: %w", err)
+	}
+
+	pw, err := writer.NewParquetWriter(file, new(ConcurrencyStep), 4)
+	if err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to create trace writer: %w", err)
+	}
+
+	for _, step := range steps {
+		if err := pw.Write(step); err != nil {
+			pw.WriteStop()
+			file.Close()
+			return "", fmt.Errorf("failed to write trace step: %w", err)
+		}
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to stop trace writer: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close trace file: %w", err)
+	}
+
+	return filePath, nil
+}
@@ -1,42 +1,100 @@
 package main
 
 import (
+	"context"
 	"time"
+
+	"r2-bench/pkg/results"
 )
 
 // RequestResult represents the result of a single GET request
 type RequestResult struct {
-	Timestamp     time.Time `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	ThreadID      int       `parquet:"name=thread_id, type=INT32"`
-	ConnID        int       `parquet:"name=conn_id, type=INT32"`
-	ObjectKey     string    `parquet:"name=object_key, type=BYTE_ARRAY, convertedtype=UTF8"`
-	RangeStart    int64     `parquet:"name=range_start, type=INT64"`
-	RangeLen      int64     `parquet:"name=range_len, type=INT64"`
-	Bytes         int64     `parquet:"name=bytes, type=INT64"`
-	LatencyMs     float64   `parquet:"name=latency_ms, type=DOUBLE"`
-	HTTPStatus    int       `parquet:"name=http_status, type=INT32"`
-	RetryCount    int       `parquet:"name=retry_count, type=INT32"`
-	ErrMsg        string    `parquet:"name=err_msg, type=BYTE_ARRAY, convertedtype=UTF8"`
-	InstanceType  string    `parquet:"name=instance_type, type=BYTE_ARRAY, convertedtype=UTF8"`
-	Concurrency   int       `parquet:"name=concurrency, type=INT32"`
-	RTTUs         int64     `parquet:"name=rtt_us, type=INT64"`
-	TCPRetx       int       `parquet:"name=tcp_retx, type=INT32"`
-	LinkUtilPct   float64   `parquet:"name=link_util_pct, type=DOUBLE"`
+	Timestamp           time.Time `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	ThreadID            int       `parquet:"name=thread_id, type=INT32"`
+	ConnID              int       `parquet:"name=conn_id, type=INT32"`
+	ObjectKey           string    `parquet:"name=object_key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RangeStart          int64     `parquet:"name=range_start, type=INT64"`
+	RangeLen            int64     `parquet:"name=range_len, type=INT64"`
+	// PartNumber is 0 for a single whole-range request, and the 1-based
+	// part index when the download manager split the range across
+	// concurrent sub-range reads (see pkg/download).
+	PartNumber          int       `parquet:"name=part_number, type=INT32"`
+	Bytes               int64     `parquet:"name=bytes, type=INT64"`
+	LatencyMs           float64   `parquet:"name=latency_ms, type=DOUBLE"`
+	HTTPStatus          int       `parquet:"name=http_status, type=INT32"`
+	RetryCount          int       `parquet:"name=retry_count, type=INT32"`
+	ErrMsg              string    `parquet:"name=err_msg, type=BYTE_ARRAY, convertedtype=UTF8"`
+	InstanceType        string    `parquet:"name=instance_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Concurrency         int       `parquet:"name=concurrency, type=INT32"`
+	RTTUs               int64     `parquet:"name=rtt_us, type=INT64"`
+	TCPRetx             int       `parquet:"name=tcp_retx, type=INT32"`
+	LinkUtilPct         float64   `parquet:"name=link_util_pct, type=DOUBLE"`
+	WorkloadType        string    `parquet:"name=workload_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	WorkloadParams      string    `parquet:"name=workload_params, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScheduledAt         time.Time `parquet:"name=scheduled_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	CoordinatedOmission bool      `parquet:"name=coordinated_omission, type=BOOLEAN"`
+	Endpoint            string    `parquet:"name=endpoint, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RequestID           string    `parquet:"name=request_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RunID               string    `parquet:"name=run_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// toRecord converts a RequestResult to the results.Record pkg/results.ParquetWriter
+// consumes. The two types can't share a definition across a main/non-main
+// package boundary, so they're kept field-for-field in sync by hand.
+func toRecord(r RequestResult) results.Record {
+	return results.Record{
+		Timestamp:           r.Timestamp,
+		ThreadID:            r.ThreadID,
+		ConnID:              r.ConnID,
+		ObjectKey:           r.ObjectKey,
+		RangeStart:          r.RangeStart,
+		RangeLen:            r.RangeLen,
+		PartNumber:          r.PartNumber,
+		Bytes:               r.Bytes,
+		LatencyMs:           r.LatencyMs,
+		HTTPStatus:          r.HTTPStatus,
+		RetryCount:          r.RetryCount,
+		ErrMsg:              r.ErrMsg,
+		InstanceType:        r.InstanceType,
+		Concurrency:         r.Concurrency,
+		RTTUs:               r.RTTUs,
+		TCPRetx:             r.TCPRetx,
+		LinkUtilPct:         r.LinkUtilPct,
+		WorkloadType:        r.WorkloadType,
+		WorkloadParams:      r.WorkloadParams,
+		ScheduledAt:         r.ScheduledAt,
+		CoordinatedOmission: r.CoordinatedOmission,
+		Endpoint:            r.Endpoint,
+		RequestID:           r.RequestID,
+		RunID:               r.RunID,
+	}
+}
+
+// StorageClient is the minimal range-read interface BenchmarkRunner needs
+// from a backend; any instances/objstore.Bucket satisfies it, so a driver
+// only has to register with objstore (see endpoint.go's buildClient) to be
+// benchmarked.
+type StorageClient interface {
+	GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
+	ObjectExists(ctx context.Context, objectKey string) (bool, error)
+	GetEndpoint() string
 }
 
 // Config holds the benchmark configuration
 type Config struct {
-	URL              string
-	InstanceType     string
-	RangeSize        int64
-	SteadyStateHours int
-	BucketName       string
-	ObjectKey        string
-	ObjectSize       int64
-	WarmupMinutes    int
-	RampStepMinutes  int
-	RampStepSize     int
-	MaxConcurrency   int
+	RunID             string
+	InstanceType      string
+	RangeSize         int64
+	SteadyStateHours  int
+	ObjectKey         string
+	ObjectSize        int64
+	WarmupMinutes     int
+	RampStepMinutes   int
+	RampStepSize      int
+	MaxConcurrency    int
+	GradientThreshold float64
+	MaxErrorRate      float64
+	MinRTTMs          float64
 }
 
 // Metrics holds aggregated metrics
@@ -54,12 +112,12 @@ type Metrics struct {
 
 // NetworkStats holds network interface statistics
 type NetworkStats struct {
-	BytesReceived    int64
-	BytesSent        int64
-	PacketsReceived  int64
-	PacketsSent      int64
-	Retransmits      int64
-	Timestamp        time.Time
+	BytesReceived   int64
+	BytesSent       int64
+	PacketsReceived int64
+	PacketsSent     int64
+	Retransmits     int64
+	Timestamp       time.Time
 }
 
 // SystemStats holds system-level statistics
@@ -68,4 +126,4 @@ type SystemStats struct {
 	IRQRate        float64
 	MemoryUsage    float64
 	Timestamp      time.Time
-} 
\ No newline at end of file
+}
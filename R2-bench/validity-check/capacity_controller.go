@@ -0,0 +1,295 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// capacityState is the AIMD state machine driving CapacityController.
+type capacityState int
+
+const (
+	// capacityProbing increments concurrency by one every window as long
+	// as both SLOs hold.
+	capacityProbing capacityState = iota
+	// capacityBackoff is the single window immediately after a halving;
+	// the next window's outcome decides whether the controller is
+	// actually stabilizing here.
+	capacityBackoff
+	// capacityStable holds concurrency steady, counting consecutive
+	// clean windows toward the knee confirmation.
+	capacityStable
+)
+
+func (s capacityState) String() string {
+	switch s {
+	case capacityProbing:
+		return "probing"
+	case capacityBackoff:
+		return "backoff"
+	case capacityStable:
+		return "stable"
+	default:
+		return "unknown"
+	}
+}
+
+// CapacityWindow records one measurement window's outcome, used both to
+// drive the controller's next decision and as a reproducible search trace.
+type CapacityWindow struct {
+	Timestamp   time.Time `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Concurrency int       `parquet:"name=concurrency, type=INT32"`
+	P99Ms       float64   `parquet:"name=p99_ms, type=DOUBLE"`
+	ErrorRate   float64   `parquet:"name=error_rate, type=DOUBLE"`
+	State       string    `parquet:"name=state, type=BYTE_ARRAY, convertedtype=UTF8"`
+	WithinSLO   bool      `parquet:"name=within_slo, type=BOOLEAN"`
+}
+
+// CapacityController is an AIMD controller for runCapacityDiscovery: it
+// increments concurrency by one every measurement window while the p99
+// latency and error rate both stay within the configured SLO, halves
+// concurrency on the first window that violates either, and declares the
+// "knee" once concurrency has held steady and within SLO for
+// stableWindows consecutive windows. This replaces having to guess a
+// -max-concurrency that happens to bracket the real saturation point.
+//
+// Each window's samples are summarized with a ring buffer (for the error
+// rate) and a P² quantile estimator (for p99), so memory use is
+// independent of how many requests a window sees.
+type CapacityController struct {
+	maxP99Ms      float64
+	maxErrorRate  float64
+	stableWindows int
+
+	state        capacityState
+	concurrency  int
+	stableStreak int
+	knee         int
+	kneeFound    bool
+	lastP99Ms    float64
+	lastErrRate  float64
+	trace        []CapacityWindow
+
+	ring     []RequestResult
+	ringSize int
+	ringPos  int
+	p99      *p2Quantile
+}
+
+// NewCapacityController seeds a controller at the given starting
+// concurrency with the SLO thresholds and window-stability target the
+// caller configured via flags.
+func NewCapacityController(initialConcurrency int, maxP99Ms, maxErrorRate float64, stableWindows, ringSize int) *CapacityController {
+	return &CapacityController{
+		maxP99Ms:      maxP99Ms,
+		maxErrorRate:  maxErrorRate,
+		stableWindows: stableWindows,
+		state:         capacityProbing,
+		concurrency:   initialConcurrency,
+		ringSize:      ringSize,
+		p99:           newP2Quantile(0.99),
+	}
+}
+
+// Observe feeds one completed request's latency and status into the
+// current window.
+func (c *CapacityController) Observe(r RequestResult) {
+	c.p99.Add(r.LatencyMs)
+	if len(c.ring) < c.ringSize {
+		c.ring = append(c.ring, r)
+		return
+	}
+	c.ring[c.ringPos] = r
+	c.ringPos = (c.ringPos + 1) % c.ringSize
+}
+
+func (c *CapacityController) errorRate() float64 {
+	if len(c.ring) == 0 {
+		return 0
+	}
+	errs := 0
+	for _, r := range c.ring {
+		if r.ErrMsg != "" {
+			errs++
+		}
+	}
+	return float64(errs) / float64(len(c.ring))
+}
+
+// EndWindow closes out the current measurement window, decides the
+// concurrency to test next, and resets the window-scoped ring buffer and
+// quantile estimator. kneeFound is true once the controller has held
+// stableWindows consecutive clean windows at the same concurrency.
+func (c *CapacityController) EndWindow() (next int, kneeFound bool) {
+	p99 := c.p99.Value()
+	errRate := c.errorRate()
+	withinSLO := p99 <= c.maxP99Ms && errRate <= c.maxErrorRate
+	c.lastP99Ms, c.lastErrRate = p99, errRate
+
+	switch {
+	case !withinSLO:
+		c.concurrency = maxInt(c.concurrency/2, 1)
+		c.state = capacityBackoff
+		c.stableStreak = 0
+	case c.state == capacityBackoff || c.state == capacityStable:
+		c.state = capacityStable
+		c.stableStreak++
+		if c.stableStreak >= c.stableWindows && !c.kneeFound {
+			c.kneeFound = true
+			c.knee = c.concurrency
+		}
+	default:
+		c.state = capacityProbing
+		c.concurrency++
+	}
+
+	c.trace = append(c.trace, CapacityWindow{
+		Timestamp:   time.Now(),
+		Concurrency: c.concurrency,
+		P99Ms:       p99,
+		ErrorRate:   errRate,
+		State:       c.state.String(),
+		WithinSLO:   withinSLO,
+	})
+
+	c.p99 = newP2Quantile(0.99)
+	c.ring = c.ring[:0]
+	c.ringPos = 0
+
+	return c.concurrency, c.kneeFound
+}
+
+// State returns the controller's current state machine label, for logging.
+func (c *CapacityController) State() string {
+	return c.state.String()
+}
+
+// LastWindow returns the p99 latency and error rate measured in the most
+// recently closed window.
+func (c *CapacityController) LastWindow() (p99Ms, errorRate float64) {
+	return c.lastP99Ms, c.lastErrRate
+}
+
+// Knee returns the concurrency level the controller converged on, once
+// EndWindow has reported kneeFound.
+func (c *CapacityController) Knee() int {
+	return c.knee
+}
+
+// Trace returns the full window-by-window search history, in order, for
+// persistence alongside the discovered knee.
+func (c *CapacityController) Trace() []CapacityWindow {
+	return c.trace
+}
+
+// p2Quantile estimates a single quantile (e.g. p99) from a stream of
+// observations using the P² algorithm (Jain & Chlamtac, 1985), which
+// tracks five markers instead of retaining every sample. This is what lets
+// CapacityController summarize an arbitrarily long measurement window in
+// constant space.
+type p2Quantile struct {
+	p    float64
+	seen int
+	init []float64
+
+	n  [5]int
+	np [5]float64
+	dn [5]float64
+	q  [5]float64
+}
+
+func newP2Quantile(p float64) *p2Quantile {
+	return &p2Quantile{p: p}
+}
+
+// Add records one observation.
+func (e *p2Quantile) Add(x float64) {
+	e.seen++
+	if e.seen <= 5 {
+		e.init = append(e.init, x)
+		if e.seen == 5 {
+			sort.Float64s(e.init)
+			for i := 0; i < 5; i++ {
+				e.q[i] = e.init[i]
+				e.n[i] = i + 1
+			}
+			e.np[0], e.np[1], e.np[2], e.np[3], e.np[4] = 1, 1+2*e.p, 1+4*e.p, 3+2*e.p, 5
+			e.dn[0], e.dn[1], e.dn[2], e.dn[3], e.dn[4] = 0, e.p/2, e.p, (1+e.p)/2, 1
+		}
+		return
+	}
+
+	k := 0
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		for i := 1; i < 5; i++ {
+			if x < e.q[i] {
+				k = i - 1
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := 0; i < 5; i++ {
+		e.np[i] += e.dn[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.np[i] - float64(e.n[i])
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			sign := 1
+			if d < 0 {
+				sign = -1
+			}
+			qNew := e.parabolic(i, sign)
+			if e.q[i-1] < qNew && qNew < e.q[i+1] {
+				e.q[i] = qNew
+			} else {
+				e.q[i] = e.linear(i, sign)
+			}
+			e.n[i] += sign
+		}
+	}
+}
+
+// Value returns the current quantile estimate. Before the fifth sample
+// arrives, it falls back to the element at index floor(p*(n-1)) of the raw
+// observations seen so far, sorted, without interpolation.
+func (e *p2Quantile) Value() float64 {
+	if e.seen == 0 {
+		return 0
+	}
+	if e.seen < 5 {
+		sorted := append([]float64{}, e.init...)
+		sort.Float64s(sorted)
+		return sorted[int(e.p*float64(len(sorted)-1))]
+	}
+	return e.q[2]
+}
+
+func (e *p2Quantile) parabolic(i, sign int) float64 {
+	d := float64(sign)
+	return e.q[i] + d/float64(e.n[i+1]-e.n[i-1])*
+		((float64(e.n[i]-e.n[i-1])+d)*(e.q[i+1]-e.q[i])/float64(e.n[i+1]-e.n[i])+
+			(float64(e.n[i+1]-e.n[i])-d)*(e.q[i]-e.q[i-1])/float64(e.n[i]-e.n[i-1]))
+}
+
+func (e *p2Quantile) linear(i, sign int) float64 {
+	j := i + sign
+	return e.q[i] + float64(sign)*(e.q[j]-e.q[i])/float64(e.n[j]-e.n[i])
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
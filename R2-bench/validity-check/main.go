@@ -0,0 +1,347 @@
+// Command validity-check runs an AIMD capacity-discovery probe against a
+// single storage endpoint: ramp concurrency up by one every window while
+// p99 latency and error rate stay within the configured SLO, halve on the
+// first window that violates either, and report the concurrency ("knee")
+// where the backend stabilizes. This is a standalone binary, not a mode of
+// the main microbenchmark command, because RequestResult and
+// ConcurrencyStep there belong to r2-bench's own package main, and a
+// package main cannot be imported by another package.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"r2-bench/instances"
+	"r2-bench/instances/objstore"
+	"r2-bench/pkg/results"
+)
+
+var (
+	url            = flag.String("url", "", "Storage endpoint URL (r2 or s3)")
+	instanceType   = flag.String("instance", "", "EC2 instance type")
+	bucketName     = flag.String("bucket", "", "Bucket name")
+	objectKey      = flag.String("object", "test-object-1gb", "Object key for testing")
+	objectSize     = flag.Int64("object-size", 1024*1024*1024, "Object size in bytes (default: 1GB)")
+	rangeSize      = flag.Int64("range-size", 100*1024*1024, "Range size in bytes (default: 100MB)")
+	concurrency    = flag.Int("concurrency", 8, "Initial concurrency level")
+	maxConcurrency = flag.Int("max-concurrency", 64, "Safety cap: abort the search if it climbs past this without stabilizing")
+	outputDir      = flag.String("output", "./output", "Output directory for results")
+	runID          = flag.String("run-id", "", "Identifier stamped on every result row (default: a generated ULID)")
+
+	capacityWindow        = flag.Duration("capacity-window", 30*time.Second, "Measurement window duration for each concurrency level tried by the capacity controller")
+	capacityMaxP99Ms      = flag.Float64("capacity-max-p99-ms", 500, "p99 latency SLO in milliseconds; a window above this triggers a backoff")
+	capacityMaxErrorRate  = flag.Float64("capacity-max-error-rate", 0.01, "Error rate SLO; a window above this triggers a backoff")
+	capacityStableWindows = flag.Int("capacity-stable-windows", 3, "Consecutive clean windows at the same concurrency required to declare the knee")
+	capacityRingSize      = flag.Int("capacity-ring-size", 500, "Ring buffer size (most recent results) used to estimate each window's error rate")
+)
+
+// RequestResult represents the result of a single GET request made during
+// capacity discovery. It carries a subset of r2-bench's own RequestResult
+// fields under the same names (see toRecord; the rest are left at their
+// zero value in the Record) since the two can't share a definition across
+// a main/main package boundary.
+type RequestResult struct {
+	Timestamp    time.Time
+	ThreadID     int
+	ConnID       int
+	ObjectKey    string
+	RangeStart   int64
+	RangeLen     int64
+	Bytes        int64
+	LatencyMs    float64
+	HTTPStatus   int
+	RetryCount   int
+	ErrMsg       string
+	InstanceType string
+	Concurrency  int
+}
+
+// toRecord converts a RequestResult to the results.Record pkg/results.ParquetWriter
+// consumes.
+func toRecord(r RequestResult, runID string) results.Record {
+	return results.Record{
+		Timestamp:    r.Timestamp,
+		ThreadID:     r.ThreadID,
+		ConnID:       r.ConnID,
+		ObjectKey:    r.ObjectKey,
+		RangeStart:   r.RangeStart,
+		RangeLen:     r.RangeLen,
+		Bytes:        r.Bytes,
+		LatencyMs:    r.LatencyMs,
+		HTTPStatus:   r.HTTPStatus,
+		RetryCount:   r.RetryCount,
+		ErrMsg:       r.ErrMsg,
+		InstanceType: r.InstanceType,
+		Concurrency:  r.Concurrency,
+		RunID:        runID,
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	if *url == "" || *instanceType == "" || *bucketName == "" {
+		slog.Error("URL, instance type, and bucket name are required")
+		os.Exit(1)
+	}
+
+	effectiveRunID := *runID
+	if effectiveRunID == "" {
+		effectiveRunID = ulid.Make().String()
+	}
+
+	slog.Info("Starting validity check", "url", *url, "instance_type", *instanceType, "run_id", effectiveRunID)
+	slog.Info("Object configuration", "object", *objectKey, "object_size", *objectSize, "range_size", *rangeSize)
+
+	// Initialize storage client
+	var client interface {
+		GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
+		ObjectExists(ctx context.Context, objectKey string) (bool, error)
+		UploadObject(ctx context.Context, objectKey string, data []byte) error
+		GetEndpoint() string
+	}
+
+	var err error
+	if isR2Endpoint(*url) {
+		// Parse R2 credentials from environment
+		accountID := os.Getenv("R2_ACCOUNT_ID")
+		accessKeyID := os.Getenv("R2_ACCESS_KEY_ID")
+		secretAccessKey := os.Getenv("R2_SECRET_ACCESS_KEY")
+
+		if accountID == "" || accessKeyID == "" || secretAccessKey == "" {
+			slog.Error("R2 credentials not found in environment variables")
+			os.Exit(1)
+		}
+
+		client, err = objstore.NewR2Bucket(accountID, accessKeyID, secretAccessKey, *bucketName)
+		if err != nil {
+			slog.Error("Failed to create R2 client", "err", err)
+			os.Exit(1)
+		}
+	} else {
+		// S3 client
+		region := extractRegionFromURL(*url)
+		client, err = objstore.NewS3Bucket(region, *bucketName)
+		if err != nil {
+			slog.Error("Failed to create S3 client", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize EC2 monitor
+	ec2Monitor, err := instances.NewEC2Monitor()
+	if err != nil {
+		slog.Warn("Failed to initialize EC2 monitor", "err", err)
+	}
+
+	// Initialize Parquet writer
+	writer, err := results.NewParquetWriter(results.WriterConfig{
+		BaseDir:      *outputDir,
+		RunID:        effectiveRunID,
+		InstanceType: *instanceType,
+	})
+	if err != nil {
+		slog.Error("Failed to create Parquet writer", "err", err)
+		os.Exit(1)
+	}
+	resultChan := make(chan results.Record, 1000)
+	ingestDone := make(chan error, 1)
+	go func() {
+		ingestDone <- writer.Ingest(context.Background(), resultChan)
+	}()
+
+	// Check if test object exists, create if not
+	ctx := context.Background()
+	exists, err := client.ObjectExists(ctx, *objectKey)
+	if err != nil {
+		slog.Error("Failed to check object existence", "err", err)
+		os.Exit(1)
+	}
+
+	if !exists {
+		slog.Info("Test object does not exist, creating...")
+		if err := createTestObject(ctx, client, *objectKey, *objectSize); err != nil {
+			slog.Error("Failed to create test object", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("Test object created successfully")
+	}
+
+	// Run capacity discovery
+	runCapacityDiscovery(ctx, client, ec2Monitor, resultChan, effectiveRunID)
+
+	close(resultChan)
+	if err := <-ingestDone; err != nil {
+		slog.Error("Error flushing Parquet writer", "err", err)
+		os.Exit(1)
+	}
+}
+
+func isR2Endpoint(url string) bool {
+	return contains(url, "r2.cloudflarestorage.com")
+}
+
+func extractRegionFromURL(url string) string {
+	// Simple region extraction for S3 URLs
+	// In practice, you might want more sophisticated parsing
+	if contains(url, "eu-central-1") {
+		return "eu-central-1"
+	}
+	return "us-east-1" // default
+}
+
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && (s == substr || (len(s) > len(substr) &&
+		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
+			contains(s[1:len(s)-1], substr))))
+}
+
+func createTestObject(ctx context.Context, client interface {
+	UploadObject(ctx context.Context, objectKey string, data []byte) error
+}, objectKey string, objectSize int64) error {
+	// Create test data (random bytes)
+	data := make([]byte, objectSize)
+	rand.Read(data)
+
+	slog.Info("Uploading test object", "bytes", objectSize, "object_key", objectKey)
+	start := time.Now()
+
+	if err := client.UploadObject(ctx, objectKey, data); err != nil {
+		return err
+	}
+
+	duration := time.Since(start)
+	throughput := float64(objectSize) / duration.Seconds() / (1024 * 1024) // MB/s
+	slog.Info("Upload completed", "duration", duration, "throughput_mbps", throughput)
+
+	return nil
+}
+
+func runCapacityDiscovery(ctx context.Context, client interface {
+	GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
+}, ec2Monitor *instances.EC2Monitor, resultChan chan<- results.Record, runID string) {
+	slog.Info("Starting capacity discovery", "initial_concurrency", *concurrency, "max_concurrency", *maxConcurrency,
+		"window", *capacityWindow, "max_p99_ms", *capacityMaxP99Ms, "max_error_rate", *capacityMaxErrorRate)
+
+	controller := NewCapacityController(*concurrency, *capacityMaxP99Ms, *capacityMaxErrorRate, *capacityStableWindows, *capacityRingSize)
+
+	resultsCh := make(chan RequestResult, 1000)
+	collectorDone := make(chan struct{})
+	go func() {
+		defer close(collectorDone)
+		for result := range resultsCh {
+			select {
+			case resultChan <- toRecord(result, runID):
+			default:
+				slog.Warn("Parquet channel full, dropping result")
+			}
+			controller.Observe(result)
+		}
+	}()
+
+	// AIMD search: +1 concurrency every window while p99 latency and error
+	// rate both stay within the configured SLO, halve on the first window
+	// that violates either, and stop once the controller has held steady
+	// for enough consecutive clean windows to call it the knee.
+	c := *concurrency
+	for {
+		slog.Info("Testing concurrency level", "concurrency", c, "state", controller.State())
+
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+		for i := 0; i < c; i++ {
+			wg.Add(1)
+			go worker(ctx, client, i, c, resultsCh, &wg, stop)
+		}
+
+		time.Sleep(*capacityWindow)
+		close(stop)
+		wg.Wait()
+
+		next, kneeFound := controller.EndWindow()
+		p99Ms, errorRate := controller.LastWindow()
+		slog.Info("Completed window", "concurrency", c, "p99_ms", p99Ms, "error_rate", errorRate,
+			"state", controller.State(), "next_concurrency", next)
+
+		if kneeFound {
+			slog.Info("Capacity discovery converged on knee", "knee_concurrency", controller.Knee())
+			break
+		}
+		if next > *maxConcurrency {
+			slog.Warn("Reached configured max concurrency before the controller stabilized", "max_concurrency", *maxConcurrency)
+			break
+		}
+		c = next
+	}
+
+	close(resultsCh)
+	<-collectorDone
+	slog.Info("Capacity discovery completed", "windows", len(controller.Trace()))
+}
+
+func worker(ctx context.Context, client interface {
+	GetObjectRange(ctx context.Context, objectKey string, start, length int64) ([]byte, error)
+}, threadID, concurrency int, resultsCh chan<- RequestResult, wg *sync.WaitGroup, stop <-chan struct{}) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		// Generate random range within object bounds
+		maxStart := *objectSize - *rangeSize
+		if maxStart <= 0 {
+			slog.Error("Range size larger than object size", "range_size", *rangeSize, "object_size", *objectSize)
+			return
+		}
+
+		rangeStart := rand.Int63n(maxStart)
+		rangeLen := *rangeSize
+		if rangeStart+rangeLen > *objectSize {
+			rangeLen = *objectSize - rangeStart
+		}
+
+		// Make request
+		reqStart := time.Now()
+		data, err := client.GetObjectRange(ctx, *objectKey, rangeStart, rangeLen)
+		latency := time.Since(reqStart)
+
+		// Record result
+		result := RequestResult{
+			Timestamp:    reqStart,
+			ThreadID:     threadID,
+			ConnID:       threadID % concurrency,
+			ObjectKey:    *objectKey,
+			RangeStart:   rangeStart,
+			RangeLen:     rangeLen,
+			Bytes:        int64(len(data)),
+			LatencyMs:    float64(latency.Microseconds()) / 1000.0,
+			HTTPStatus:   200,
+			RetryCount:   0,
+			InstanceType: *instanceType,
+			Concurrency:  concurrency,
+		}
+
+		if err != nil {
+			result.ErrMsg = err.Error()
+			result.HTTPStatus = 500
+		}
+
+		select {
+		case resultsCh <- result:
+		default:
+			slog.Warn("Results channel full, dropping result")
+		}
+	}
+}
@@ -2,634 +2,269 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-)
-
-// GrafanaDashboard represents a Grafana dashboard configuration
-type GrafanaDashboard struct {
-	Dashboard DashboardConfig `json:"dashboard"`
-	FolderID  int             `json:"folderId"`
-	Overwrite bool            `json:"overwrite"`
-}
-
-// DashboardConfig represents the dashboard configuration
-type DashboardConfig struct {
-	ID          interface{} `json:"id"`
-	Title       string      `json:"title"`
-	Tags        []string    `json:"tags"`
-	Style       string      `json:"style"`
-	Timezone    string      `json:"timezone"`
-	Panels      []Panel     `json:"panels"`
-	Time        TimeRange   `json:"time"`
-	Timepicker  Timepicker  `json:"timepicker"`
-	Templating  Templating  `json:"templating"`
-	Annotations Annotations `json:"annotations"`
-	Refresh     string      `json:"refresh"`
-	SchemaVersion int       `json:"schemaVersion"`
-	Version     int         `json:"version"`
-	Links       []Link      `json:"links"`
-}
-
-// Panel represents a Grafana panel
-type Panel struct {
-	ID          int         `json:"id"`
-	Title       string      `json:"title"`
-	Type        string      `json:"type"`
-	GridPos     GridPos     `json:"gridPos"`
-	Targets     []Target    `json:"targets"`
-	FieldConfig FieldConfig `json:"fieldConfig"`
-	Options     interface{} `json:"options,omitempty"`
-}
-
-// GridPos represents panel grid position
-type GridPos struct {
-	H int `json:"h"`
-	W int `json:"w"`
-	X int `json:"x"`
-	Y int `json:"y"`
-}
-
-// Target represents a query target
-type Target struct {
-	Expr         string `json:"expr"`
-	LegendFormat string `json:"legendFormat,omitempty"`
-	RefID        string `json:"refId"`
-}
-
-// FieldConfig represents field configuration
-type FieldConfig struct {
-	Defaults Defaults `json:"defaults"`
-}
-
-// Defaults represents default field settings
-type Defaults struct {
-	Color       Color  `json:"color"`
-	Custom      Custom `json:"custom"`
-	Mappings    []interface{} `json:"mappings"`
-	Thresholds  Thresholds    `json:"thresholds"`
-	Unit        string        `json:"unit"`
-}
+	"strings"
 
-// Color represents color configuration
-type Color struct {
-	Mode string `json:"mode"`
-}
-
-// Custom represents custom field configuration
-type Custom struct {
-	AxisLabel     string `json:"axisLabel"`
-	AxisPlacement string `json:"axisPlacement"`
-	BarAlignment  int    `json:"barAlignment"`
-	DrawStyle     string `json:"drawStyle"`
-	FillOpacity   int    `json:"fillOpacity"`
-	GradientMode  string `json:"gradientMode"`
-	HideFrom      HideFrom `json:"hideFrom"`
-	LineInterpolation string `json:"lineInterpolation"`
-	LineWidth     int    `json:"lineWidth"`
-	PointSize     int    `json:"pointSize"`
-	ScaleDistribution ScaleDistribution `json:"scaleDistribution"`
-	ShowPoints    string `json:"showPoints"`
-	SpanNulls     bool   `json:"spanNulls"`
-	Stacking      Stacking `json:"stacking"`
-	ThresholdsStyle ThresholdsStyle `json:"thresholdsStyle"`
-}
-
-// HideFrom represents hide configuration
-type HideFrom struct {
-	Legend  bool `json:"legend"`
-	Tooltip bool `json:"tooltip"`
-	Viz     bool `json:"viz"`
-}
-
-// ScaleDistribution represents scale distribution
-type ScaleDistribution struct {
-	Type string `json:"type"`
-}
-
-// Stacking represents stacking configuration
-type Stacking struct {
-	Group string `json:"group"`
-	Mode  string `json:"mode"`
-}
+	"r2-bench/pkg/alerting"
+	"r2-bench/pkg/dashboard"
+)
 
-// ThresholdsStyle represents thresholds style
-type ThresholdsStyle struct {
-	Mode string `json:"mode"`
-}
+var (
+	grafanaURL      = flag.String("grafana-url", "", "Grafana base URL (e.g. https://grafana.example.com); enables uploading dashboards via the HTTP API instead of just writing JSON")
+	grafanaAPIKey   = flag.String("grafana-api-key", "", "Grafana API key / service account token (falls back to the GRAFANA_API_KEY env var)")
+	grafanaFolder   = flag.String("folder", "R2 Benchmark", "Grafana folder to file dashboards under")
+	datasourceUID   = flag.String("datasource-uid", "", "UID to provision the benchmark's Prometheus datasource with, pointing at -datasource-url")
+	datasourceURL   = flag.String("datasource-url", "http://localhost:9100", "Scrape URL of the benchmark's Prometheus datasource, used with -datasource-uid")
+	provisioningDir = flag.String("provisioning-dir", "", "If set, also write a Grafana file-provisioning bundle (dashboards/*.yaml provider config + dashboards/*.json) under this directory, e.g. /etc/grafana/provisioning")
+	alertsOut       = flag.String("alerts-out", "", "If set, write the SLO burn-rate Prometheus rule file (rule_files: entry) to this path")
+)
 
-// Thresholds represents thresholds configuration
-type Thresholds struct {
-	Mode  string        `json:"mode"`
-	Steps []ThresholdStep `json:"steps"`
-}
+// r2BenchFilter is the label matcher applied to every query on the standard
+// dashboard so a single dashboard covers many runs, instance types, and
+// concurrency levels instead of hardcoding one combination per series.
+const r2BenchFilter = `run_id=~"$run_id", instance_type=~"$instance_type", concurrency=~"$concurrency"`
 
-// ThresholdStep represents a threshold step
-type ThresholdStep struct {
-	Color string  `json:"color"`
-	Value float64 `json:"value"`
-}
-
-// TimeRange represents time range
-type TimeRange struct {
-	From string `json:"from"`
-	To   string `json:"to"`
+// CreateR2BenchmarkDashboard builds the standard single-run dashboard:
+// throughput, latency percentiles, QPS, error rate, concurrency, and host
+// resource usage. Template variables let the same dashboard be filtered down
+// to one run/instance type/concurrency level, or compare several at once.
+func CreateR2BenchmarkDashboard() (*dashboard.GrafanaDashboard, error) {
+	return dashboard.NewDashboard("R2 Benchmark Dashboard").
+		WithTag("r2").WithTag("benchmark").WithTag("performance").
+		WithQueryVariable("run_id", `label_values(r2_bench_throughput_mbps, run_id)`).
+		WithQueryVariable("instance_type", `label_values(r2_bench_throughput_mbps, instance_type)`).
+		WithQueryVariable("concurrency", `label_values(r2_bench_throughput_mbps{run_id=~"$run_id", instance_type=~"$instance_type"}, concurrency)`).
+		WithIntervalVariable("range", []string{"1m", "5m", "15m", "1h"}).
+		AddPanel(dashboard.NewTimeseriesPanel("Throughput (Mbps)").
+			WithQuery(fmt.Sprintf(`r2_bench_throughput_mbps{%s}`, r2BenchFilter), "{{instance_type}} - C{{concurrency}} - {{run_id}}").
+			WithUnit("Mbps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "red", Value: 80},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 0, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("Latency (ms)").
+			WithQuery(fmt.Sprintf(`histogram_quantile(0.50, rate(r2_bench_latency_ms_bucket{%s}[$range]))`, r2BenchFilter), "P50 - {{instance_type}} - {{run_id}}").
+			WithQuery(fmt.Sprintf(`histogram_quantile(0.90, rate(r2_bench_latency_ms_bucket{%s}[$range]))`, r2BenchFilter), "P90 - {{instance_type}} - {{run_id}}").
+			WithQuery(fmt.Sprintf(`histogram_quantile(0.95, rate(r2_bench_latency_ms_bucket{%s}[$range]))`, r2BenchFilter), "P95 - {{instance_type}} - {{run_id}}").
+			WithQuery(fmt.Sprintf(`histogram_quantile(0.99, rate(r2_bench_latency_ms_bucket{%s}[$range]))`, r2BenchFilter), "P99 - {{instance_type}} - {{run_id}}").
+			WithUnit("ms").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 100},
+				dashboard.ThresholdStep{Color: "red", Value: 500},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 12, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("Requests per Second").
+			WithQuery(fmt.Sprintf(`rate(r2_bench_requests_total{%s}[$range])`, r2BenchFilter), "{{instance_type}} - C{{concurrency}} - {{run_id}}").
+			WithUnit("reqps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "red", Value: 1000},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 0, Y: 8})).
+		AddPanel(dashboard.NewTimeseriesPanel("Error Rate").
+			WithQuery(fmt.Sprintf(`rate(r2_bench_errors_total{%s}[$range])`, r2BenchFilter), "{{instance_type}} - {{error_type}} - {{run_id}}").
+			WithUnit("reqps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 0.1},
+				dashboard.ThresholdStep{Color: "red", Value: 1},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 12, Y: 8})).
+		AddPanel(dashboard.NewStatPanel("Current Concurrency").
+			WithQuery(fmt.Sprintf(`r2_bench_concurrency{%s}`, r2BenchFilter), "{{instance_type}} - {{run_id}}").
+			WithUnit("short").
+			WithColorMode("thresholds").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 50},
+				dashboard.ThresholdStep{Color: "red", Value: 100},
+			).
+			WithOptions(map[string]interface{}{
+				"colorMode":   "value",
+				"graphMode":   "area",
+				"justifyMode": "auto",
+				"orientation": "auto",
+				"reduceOptions": map[string]interface{}{
+					"calcs":  []string{"lastNotNull"},
+					"fields": "",
+					"values": false,
+				},
+				"textMode": "auto",
+			}).
+			At(dashboard.GridPos{H: 4, W: 6, X: 0, Y: 16})).
+		AddPanel(dashboard.NewTimeseriesPanel("CPU Utilization").
+			WithQuery(fmt.Sprintf(`r2_bench_cpu_utilization{%s}`, r2BenchFilter), "{{instance_type}} - {{run_id}}").
+			WithUnit("percent").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 70},
+				dashboard.ThresholdStep{Color: "red", Value: 90},
+			).
+			At(dashboard.GridPos{H: 4, W: 6, X: 6, Y: 16})).
+		AddPanel(dashboard.NewTimeseriesPanel("Network Utilization").
+			WithQuery(fmt.Sprintf(`r2_bench_network_utilization{metric="link_utilization", %s}`, r2BenchFilter), "{{instance_type}} - {{run_id}}").
+			WithUnit("percent").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 70},
+				dashboard.ThresholdStep{Color: "red", Value: 90},
+			).
+			At(dashboard.GridPos{H: 4, W: 6, X: 12, Y: 16})).
+		AddPanel(dashboard.NewTimeseriesPanel("TCP Retransmits").
+			WithQuery(fmt.Sprintf(`r2_bench_network_utilization{metric="tcp_retransmits", %s}`, r2BenchFilter), "{{instance_type}} - {{run_id}}").
+			WithUnit("short").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 10},
+				dashboard.ThresholdStep{Color: "red", Value: 100},
+			).
+			At(dashboard.GridPos{H: 4, W: 6, X: 18, Y: 16})).
+		Build()
 }
 
-// Timepicker represents timepicker configuration
-type Timepicker struct {
-	RefreshIntervals []string `json:"refresh_intervals"`
+// CreateWriteBenchmarkDashboard builds a dashboard for a write/PUT
+// benchmark run, mirroring the read dashboard's layout with the write-side
+// metrics.
+func CreateWriteBenchmarkDashboard() (*dashboard.GrafanaDashboard, error) {
+	return dashboard.NewDashboard("R2 Write Benchmark Dashboard").
+		WithTag("r2").WithTag("benchmark").WithTag("write").
+		AddPanel(dashboard.NewTimeseriesPanel("Write Throughput (Mbps)").
+			WithQuery(`r2_bench_write_throughput_mbps`, "{{instance_type}} - C{{concurrency}}").
+			WithUnit("Mbps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "red", Value: 80},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 0, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("Write Latency (ms)").
+			WithQuery(`histogram_quantile(0.50, rate(r2_bench_write_latency_ms_bucket[5m]))`, "P50 - {{instance_type}}").
+			WithQuery(`histogram_quantile(0.99, rate(r2_bench_write_latency_ms_bucket[5m]))`, "P99 - {{instance_type}}").
+			WithUnit("ms").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 100},
+				dashboard.ThresholdStep{Color: "red", Value: 500},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 12, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("Write Error Rate").
+			WithQuery(`rate(r2_bench_write_errors_total[5m])`, "{{instance_type}} - {{error_type}}").
+			WithUnit("reqps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 0.1},
+				dashboard.ThresholdStep{Color: "red", Value: 1},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 0, Y: 8})).
+		Build()
 }
 
-// Templating represents templating configuration
-type Templating struct {
-	List []interface{} `json:"list"`
+// CreateMixedWorkloadDashboard builds a dashboard for a comparative run
+// (see chunk0-4's multi-endpoint support), with an `endpoint` variable so a
+// single dashboard can be filtered down to one backend at a time.
+func CreateMixedWorkloadDashboard() (*dashboard.GrafanaDashboard, error) {
+	return dashboard.NewDashboard("R2 Mixed Workload Dashboard").
+		WithTag("r2").WithTag("benchmark").WithTag("mixed").
+		WithVariable(dashboard.TemplateVariable{
+			Name:    "endpoint",
+			Label:   "Endpoint",
+			Type:    "query",
+			Query:   "label_values(r2_bench_throughput_mbps, endpoint)",
+			Refresh: 2,
+			Multi:   true,
+		}).
+		AddPanel(dashboard.NewTimeseriesPanel("Throughput by Endpoint (Mbps)").
+			WithQuery(`r2_bench_throughput_mbps{endpoint=~"$endpoint"}`, "{{endpoint}} - C{{concurrency}}").
+			WithUnit("Mbps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "red", Value: 80},
+			).
+			At(dashboard.GridPos{H: 8, W: 24, X: 0, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("Error Rate by Endpoint").
+			WithQuery(`rate(r2_bench_errors_total{endpoint=~"$endpoint"}[5m])`, "{{endpoint}} - {{error_type}}").
+			WithUnit("reqps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 0.1},
+				dashboard.ThresholdStep{Color: "red", Value: 1},
+			).
+			At(dashboard.GridPos{H: 8, W: 24, X: 0, Y: 8})).
+		Build()
 }
 
-// Annotations represents annotations configuration
-type Annotations struct {
-	List []interface{} `json:"list"`
+// CreateInstanceComparisonDashboard builds a dashboard for comparing the
+// same run across EC2 instance types, templated on instance_type so the
+// legend and underlying query both scope to the selected set.
+func CreateInstanceComparisonDashboard() (*dashboard.GrafanaDashboard, error) {
+	return dashboard.NewDashboard("R2 Instance Type Comparison Dashboard").
+		WithTag("r2").WithTag("benchmark").WithTag("comparison").
+		WithVariable(dashboard.TemplateVariable{
+			Name:    "instance_type",
+			Label:   "Instance Type",
+			Type:    "query",
+			Query:   "label_values(r2_bench_throughput_mbps, instance_type)",
+			Refresh: 2,
+			Multi:   true,
+		}).
+		AddPanel(dashboard.NewTimeseriesPanel("Throughput by Instance Type (Mbps)").
+			WithQuery(`r2_bench_throughput_mbps{instance_type=~"$instance_type"}`, "{{instance_type}} - C{{concurrency}}").
+			WithUnit("Mbps").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "red", Value: 80},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 0, Y: 0})).
+		AddPanel(dashboard.NewTimeseriesPanel("P99 Latency by Instance Type (ms)").
+			WithQuery(`histogram_quantile(0.99, rate(r2_bench_latency_ms_bucket{instance_type=~"$instance_type"}[5m]))`, "{{instance_type}}").
+			WithUnit("ms").
+			WithThresholds(
+				dashboard.ThresholdStep{Color: "green", Value: 0},
+				dashboard.ThresholdStep{Color: "yellow", Value: 100},
+				dashboard.ThresholdStep{Color: "red", Value: 500},
+			).
+			At(dashboard.GridPos{H: 8, W: 12, X: 12, Y: 0})).
+		Build()
 }
 
-// Link represents a dashboard link
-type Link struct {
-	AsDropdown  bool   `json:"asDropdown"`
-	Icon        string `json:"icon"`
-	IncludeVars bool   `json:"includeVars"`
-	Tags        []string `json:"tags"`
-	TargetBlank bool   `json:"targetBlank"`
-	Title       string `json:"title"`
-	Tooltip     string `json:"tooltip"`
-	Type        string `json:"type"`
-	URL         string `json:"url"`
+// r2BenchSLOs are the SLOs this generator ships burn-rate alerts for. The
+// error-ratio queries are unscoped (no run_id/instance_type filter) since
+// alerting rules, unlike dashboard panels, need one fixed series to
+// evaluate rather than a user-selectable set. The read-latency SLO counts
+// requests under 512ms, the r2_bench_latency_ms bucket boundary nearest
+// 500ms (its buckets are powers of two).
+var r2BenchSLOs = []alerting.SLO{
+	{
+		Name:            "availability",
+		ErrorRatioQuery: `rate(r2_bench_errors_total[{{window}}]) / rate(r2_bench_requests_total[{{window}}])`,
+		Objective:       0.999,
+	},
+	{
+		Name:            "read-latency",
+		ErrorRatioQuery: `1 - (sum(rate(r2_bench_latency_ms_bucket{le="512"}[{{window}}])) / sum(rate(r2_bench_latency_ms_count[{{window}}])))`,
+		Objective:       0.99,
+	},
 }
 
-// CreateR2BenchmarkDashboard creates a Grafana dashboard for R2 benchmarking
-func CreateR2BenchmarkDashboard() *GrafanaDashboard {
-	return &GrafanaDashboard{
-		Dashboard: DashboardConfig{
-			ID:          nil,
-			Title:       "R2 Benchmark Dashboard",
-			Tags:        []string{"r2", "benchmark", "performance"},
-			Style:       "dark",
-			Timezone:    "browser",
-			SchemaVersion: 30,
-			Version:     1,
-			Refresh:     "10s",
-			Time: TimeRange{
-				From: "now-1h",
-				To:   "now",
-			},
-			Timepicker: Timepicker{
-				RefreshIntervals: []string{"5s", "10s", "30s", "1m", "5m", "15m", "30m", "1h", "2h", "1d"},
-			},
-			Templating:  Templating{List: []interface{}{}},
-			Annotations: Annotations{List: []interface{}{}},
-			Links:       []Link{},
-			Panels: []Panel{
-				// Throughput Panel
-				{
-					ID:    1,
-					Title: "Throughput (Mbps)",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 8,
-						W: 12,
-						X: 0,
-						Y: 0,
-					},
-					Targets: []Target{
-						{
-							Expr:         `r2_bench_throughput_mbps`,
-							LegendFormat: "{{instance_type}} - C{{concurrency}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "red", Value: 80},
-								},
-							},
-							Unit: "Mbps",
-						},
-					},
-				},
-				// Latency Panel
-				{
-					ID:    2,
-					Title: "Latency (ms)",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 8,
-						W: 12,
-						X: 12,
-						Y: 0,
-					},
-					Targets: []Target{
-						{
-							Expr:         `histogram_quantile(0.50, rate(r2_bench_latency_ms_bucket[5m]))`,
-							LegendFormat: "P50 - {{instance_type}}",
-							RefID:        "A",
-						},
-						{
-							Expr:         `histogram_quantile(0.90, rate(r2_bench_latency_ms_bucket[5m]))`,
-							LegendFormat: "P90 - {{instance_type}}",
-							RefID:        "B",
-						},
-						{
-							Expr:         `histogram_quantile(0.95, rate(r2_bench_latency_ms_bucket[5m]))`,
-							LegendFormat: "P95 - {{instance_type}}",
-							RefID:        "C",
-						},
-						{
-							Expr:         `histogram_quantile(0.99, rate(r2_bench_latency_ms_bucket[5m]))`,
-							LegendFormat: "P99 - {{instance_type}}",
-							RefID:        "D",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 100},
-									{Color: "red", Value: 500},
-								},
-							},
-							Unit: "ms",
-						},
-					},
-				},
-				// QPS Panel
-				{
-					ID:    3,
-					Title: "Requests per Second",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 8,
-						W: 12,
-						X: 0,
-						Y: 8,
-					},
-					Targets: []Target{
-						{
-							Expr:         `rate(r2_bench_requests_total[5m])`,
-							LegendFormat: "{{instance_type}} - C{{concurrency}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "red", Value: 1000},
-								},
-							},
-							Unit: "reqps",
-						},
-					},
-				},
-				// Error Rate Panel
-				{
-					ID:    4,
-					Title: "Error Rate",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 8,
-						W: 12,
-						X: 12,
-						Y: 8,
-					},
-					Targets: []Target{
-						{
-							Expr:         `rate(r2_bench_errors_total[5m])`,
-							LegendFormat: "{{instance_type}} - {{error_type}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 0.1},
-									{Color: "red", Value: 1},
-								},
-							},
-							Unit: "reqps",
-						},
-					},
-				},
-				// Concurrency Panel
-				{
-					ID:    5,
-					Title: "Current Concurrency",
-					Type:  "stat",
-					GridPos: GridPos{
-						H: 4,
-						W: 6,
-						X: 0,
-						Y: 16,
-					},
-					Targets: []Target{
-						{
-							Expr:         `r2_bench_concurrency`,
-							LegendFormat: "{{instance_type}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "thresholds"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 50},
-									{Color: "red", Value: 100},
-								},
-							},
-							Unit: "short",
-						},
-					},
-					Options: map[string]interface{}{
-						"colorMode":   "value",
-						"graphMode":   "area",
-						"justifyMode": "auto",
-						"orientation": "auto",
-						"reduceOptions": map[string]interface{}{
-							"calcs": []string{"lastNotNull"},
-							"fields": "",
-							"values": false,
-						},
-						"textMode": "auto",
-					},
-				},
-				// CPU Utilization Panel
-				{
-					ID:    6,
-					Title: "CPU Utilization",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 4,
-						W: 6,
-						X: 6,
-						Y: 16,
-					},
-					Targets: []Target{
-						{
-							Expr:         `r2_bench_cpu_utilization`,
-							LegendFormat: "{{instance_type}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 70},
-									{Color: "red", Value: 90},
-								},
-							},
-							Unit: "percent",
-						},
-					},
-				},
-				// Network Utilization Panel
-				{
-					ID:    7,
-					Title: "Network Utilization",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 4,
-						W: 6,
-						X: 12,
-						Y: 16,
-					},
-					Targets: []Target{
-						{
-							Expr:         `r2_bench_network_utilization{metric="link_utilization"}`,
-							LegendFormat: "{{instance_type}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 70},
-									{Color: "red", Value: 90},
-								},
-							},
-							Unit: "percent",
-						},
-					},
-				},
-				// TCP Retransmits Panel
-				{
-					ID:    8,
-					Title: "TCP Retransmits",
-					Type:  "timeseries",
-					GridPos: GridPos{
-						H: 4,
-						W: 6,
-						X: 18,
-						Y: 16,
-					},
-					Targets: []Target{
-						{
-							Expr:         `r2_bench_network_utilization{metric="tcp_retransmits"}`,
-							LegendFormat: "{{instance_type}}",
-							RefID:        "A",
-						},
-					},
-					FieldConfig: FieldConfig{
-						Defaults: Defaults{
-							Color: Color{Mode: "palette-classic"},
-							Custom: Custom{
-								AxisLabel:     "",
-								AxisPlacement: "auto",
-								BarAlignment:  0,
-								DrawStyle:     "line",
-								FillOpacity:   10,
-								GradientMode:  "none",
-								HideFrom:      HideFrom{Legend: false, Tooltip: false, Viz: false},
-								LineInterpolation: "linear",
-								LineWidth:     1,
-								PointSize:     5,
-								ScaleDistribution: ScaleDistribution{Type: "linear"},
-								ShowPoints:    "never",
-								SpanNulls:     false,
-								Stacking:      Stacking{Group: "A", Mode: "none"},
-								ThresholdsStyle: ThresholdsStyle{Mode: "off"},
-							},
-							Mappings: []interface{}{},
-							Thresholds: Thresholds{
-								Mode: "absolute",
-								Steps: []ThresholdStep{
-									{Color: "green", Value: 0},
-									{Color: "yellow", Value: 10},
-									{Color: "red", Value: 100},
-								},
-							},
-							Unit: "short",
-						},
-					},
-				},
-			},
-		},
-		FolderID:  0,
-		Overwrite: true,
+// CreateR2BenchSLORules builds the Prometheus rule file backing the
+// benchmark's SLOs: a recording-rule group per SLO's distinct lookback
+// windows, plus the Google SRE workbook's multi-window, multi-burn-rate
+// alerts reading those recorded series (see pkg/alerting).
+func CreateR2BenchSLORules() (*alerting.RuleFile, error) {
+	b := alerting.NewRuleFile()
+	for _, slo := range r2BenchSLOs {
+		recording, alerts := alerting.BuildSLORuleGroups(slo)
+		b.AddGroup(recording).AddGroup(alerts)
 	}
+	return b.Build()
 }
 
 // SaveDashboard saves the dashboard configuration to a JSON file
-func SaveDashboard(dashboard *GrafanaDashboard, outputPath string) error {
+func SaveDashboard(dash *dashboard.GrafanaDashboard, outputPath string) error {
 	// Create output directory if it doesn't exist
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -637,7 +272,7 @@ func SaveDashboard(dashboard *GrafanaDashboard, outputPath string) error {
 	}
 
 	// Marshal to JSON
-	data, err := json.MarshalIndent(dashboard, "", "  ")
+	data, err := json.MarshalIndent(dash, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal dashboard: %w", err)
 	}
@@ -651,15 +286,92 @@ func SaveDashboard(dashboard *GrafanaDashboard, outputPath string) error {
 	return nil
 }
 
+// dashboardConstructors maps each dashboard's file-name stem (used for both
+// the JSON output path and the provisioning bundle entry name) to its
+// builder function.
+var dashboardConstructors = map[string]func() (*dashboard.GrafanaDashboard, error){
+	"r2-benchmark-dashboard":           CreateR2BenchmarkDashboard,
+	"r2-write-benchmark-dashboard":     CreateWriteBenchmarkDashboard,
+	"r2-mixed-workload-dashboard":      CreateMixedWorkloadDashboard,
+	"r2-instance-comparison-dashboard": CreateInstanceComparisonDashboard,
+}
+
 func main() {
-	dashboard := CreateR2BenchmarkDashboard()
-	
-	outputPath := "grafana/r2-benchmark-dashboard.json"
-	if err := SaveDashboard(dashboard, outputPath); err != nil {
-		fmt.Printf("Error saving dashboard: %v\n", err)
+	flag.Parse()
+
+	dashboards := make(map[string]*dashboard.GrafanaDashboard, len(dashboardConstructors))
+	for name, create := range dashboardConstructors {
+		dash, err := create()
+		if err != nil {
+			fmt.Printf("Error building dashboard %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		dashboards[name] = dash
+		if err := SaveDashboard(dash, filepath.Join("grafana", name+".json")); err != nil {
+			fmt.Printf("Error saving dashboard: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Println("Grafana dashboard configurations created successfully!")
+	fmt.Println("You can import these dashboards into Grafana using the JSON files.")
+
+	if *provisioningDir != "" {
+		if err := dashboard.WriteProvisioningBundle(dashboards, *provisioningDir, *grafanaFolder); err != nil {
+			fmt.Printf("Error writing provisioning bundle: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Provisioning bundle written to %s\n", *provisioningDir)
+	}
+
+	if *alertsOut != "" {
+		rules, err := CreateR2BenchSLORules()
+		if err != nil {
+			fmt.Printf("Error building SLO alerting rules: %v\n", err)
+			os.Exit(1)
+		}
+		if err := alerting.WriteRuleFile(rules, *alertsOut); err != nil {
+			fmt.Printf("Error writing alerting rules: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Alerting rules written to %s\n", *alertsOut)
+	}
+
+	if *grafanaURL == "" {
+		return
+	}
+
+	apiKey := *grafanaAPIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("GRAFANA_API_KEY")
+	}
+	client := dashboard.NewGrafanaClient(strings.TrimRight(*grafanaURL, "/"), apiKey)
+
+	if *datasourceUID != "" {
+		err := client.EnsureDatasource(dashboard.DatasourceConfig{
+			Name:   "r2-bench-prometheus",
+			Type:   "prometheus",
+			URL:    *datasourceURL,
+			Access: "proxy",
+			UID:    *datasourceUID,
+		})
+		if err != nil {
+			fmt.Printf("Error provisioning datasource: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Datasource %q provisioned at %s\n", *datasourceUID, *datasourceURL)
+	}
+
+	folderUID, err := client.EnsureFolder(*grafanaFolder)
+	if err != nil {
+		fmt.Printf("Error ensuring folder %q: %v\n", *grafanaFolder, err)
 		os.Exit(1)
 	}
-	
-	fmt.Println("Grafana dashboard configuration created successfully!")
-	fmt.Println("You can import this dashboard into Grafana using the JSON file.")
+
+	for name, dash := range dashboards {
+		if err := client.UploadDashboard(dash, folderUID); err != nil {
+			fmt.Printf("Error uploading dashboard %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Uploaded dashboard %s to %s (folder %q)\n", name, *grafanaURL, *grafanaFolder)
+	}
 }
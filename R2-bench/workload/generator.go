@@ -0,0 +1,221 @@
+// Package workload provides pluggable access-pattern generators for the
+// benchmark worker loop, so range-read traffic can model realistic
+// Parquet/Iceberg query patterns rather than only synthetic uniform loads.
+package workload
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// Generator produces the next request to issue against the object under
+// test. Implementations must be safe for concurrent use by multiple
+// workers.
+type Generator interface {
+	// NextRequest returns the object key and byte range for the next
+	// request to issue.
+	NextRequest(ctx context.Context) (objectKey string, start, length int64)
+
+	// Name identifies the pattern for logging and for stamping onto
+	// RequestResult rows.
+	Name() string
+
+	// Params returns the pattern parameters that produced the last
+	// request, formatted for storage alongside the result.
+	Params() string
+}
+
+// Config carries the parameters common to every generator plus the
+// pattern-specific knobs selected via CLI flags.
+type Config struct {
+	ObjectKey  string
+	ObjectSize int64
+	RangeSize  int64
+
+	// Stride is the byte offset added between successive reads in the
+	// sequential generator.
+	Stride int64
+
+	// ZipfTheta controls the skew of the zipfian generator (values <= 0
+	// fall back to 1.0; higher values concentrate more traffic on the head
+	// keys).
+	ZipfTheta float64
+	// NumKeys is the number of distinct hot-key ranges the zipfian
+	// generator draws from.
+	NumKeys int
+
+	// HotsetPct is the fraction of requests (0-1) routed to HotRangePct
+	// of the object's range space.
+	HotsetPct    float64
+	HotRangePct  float64
+
+	// TraceFile is a CSV of `timestamp,key,offset,length` rows replayed
+	// by the trace-replay generator.
+	TraceFile string
+}
+
+// New builds the named generator. Supported names: "uniform" (default),
+// "sequential", "zipfian", "hotset", "trace-replay".
+func New(name string, cfg Config) (Generator, error) {
+	switch name {
+	case "", "uniform":
+		return NewUniform(cfg), nil
+	case "sequential":
+		return NewSequential(cfg), nil
+	case "zipfian":
+		return NewZipfian(cfg), nil
+	case "hotset":
+		return NewHotset(cfg), nil
+	case "trace-replay":
+		return NewTraceReplay(cfg)
+	default:
+		return nil, fmt.Errorf("unknown workload generator %q", name)
+	}
+}
+
+func maxStart(cfg Config) int64 {
+	m := cfg.ObjectSize - cfg.RangeSize
+	if m < 0 {
+		return 0
+	}
+	return m
+}
+
+func clampLength(cfg Config, start int64) int64 {
+	length := cfg.RangeSize
+	if start+length > cfg.ObjectSize {
+		length = cfg.ObjectSize - start
+	}
+	return length
+}
+
+// Uniform reads uniformly random ranges within the object, matching the
+// benchmark's original behavior.
+type Uniform struct {
+	cfg Config
+}
+
+// NewUniform builds the uniform-random generator.
+func NewUniform(cfg Config) *Uniform {
+	return &Uniform{cfg: cfg}
+}
+
+func (u *Uniform) NextRequest(ctx context.Context) (string, int64, int64) {
+	max := maxStart(u.cfg)
+	start := int64(0)
+	if max > 0 {
+		start = rand.Int63n(max)
+	}
+	return u.cfg.ObjectKey, start, clampLength(u.cfg, start)
+}
+
+func (u *Uniform) Name() string   { return "uniform" }
+func (u *Uniform) Params() string { return "" }
+
+// Sequential scans the object from the start with a configurable stride,
+// wrapping around once it passes the end.
+type Sequential struct {
+	cfg    Config
+	offset int64
+}
+
+// NewSequential builds the sequential-scan generator.
+func NewSequential(cfg Config) *Sequential {
+	if cfg.Stride <= 0 {
+		cfg.Stride = cfg.RangeSize
+	}
+	return &Sequential{cfg: cfg}
+}
+
+func (s *Sequential) NextRequest(ctx context.Context) (string, int64, int64) {
+	start := atomic.AddInt64(&s.offset, s.cfg.Stride) - s.cfg.Stride
+	max := maxStart(s.cfg)
+	if max > 0 {
+		start = start % max
+	} else {
+		start = 0
+	}
+	return s.cfg.ObjectKey, start, clampLength(s.cfg, start)
+}
+
+func (s *Sequential) Name() string   { return "sequential" }
+func (s *Sequential) Params() string { return fmt.Sprintf("stride=%d", s.cfg.Stride) }
+
+// Zipfian skews traffic towards a small set of "hot" key ranges using a
+// Zipf distribution with tunable theta.
+type Zipfian struct {
+	cfg  Config
+	mu   sync.Mutex
+	zipf *rand.Zipf
+}
+
+// NewZipfian builds the zipfian hot-key generator over NumKeys equally
+// sized ranges spanning the object.
+func NewZipfian(cfg Config) *Zipfian {
+	if cfg.NumKeys <= 0 {
+		cfg.NumKeys = 100
+	}
+	if cfg.ZipfTheta <= 0 {
+		cfg.ZipfTheta = 1.0
+	}
+	// rand.NewZipf wants s > 1; theta in (0,1) maps to s close to 1.
+	s := 1.0 + cfg.ZipfTheta
+	z := rand.NewZipf(rand.New(rand.NewSource(1)), s, 1, uint64(cfg.NumKeys-1))
+	return &Zipfian{cfg: cfg, zipf: z}
+}
+
+func (z *Zipfian) NextRequest(ctx context.Context) (string, int64, int64) {
+	rangeSpan := int64(math.Max(float64(z.cfg.ObjectSize/int64(z.cfg.NumKeys)), 1))
+	// rand.Zipf.Uint64 draws from an unexported, unsynchronized
+	// rand.Rand source, so concurrent workers must serialize here.
+	z.mu.Lock()
+	key := z.zipf.Uint64()
+	z.mu.Unlock()
+	start := int64(key) * rangeSpan
+	if start+z.cfg.RangeSize > z.cfg.ObjectSize {
+		start = maxStart(z.cfg)
+	}
+	return z.cfg.ObjectKey, start, clampLength(z.cfg, start)
+}
+
+func (z *Zipfian) Name() string { return "zipfian" }
+func (z *Zipfian) Params() string {
+	return fmt.Sprintf("theta=%.2f,keys=%d", z.cfg.ZipfTheta, z.cfg.NumKeys)
+}
+
+// Hotset sends HotsetPct of requests to HotRangePct of the object's range
+// space, modeling a small number of frequently scanned row groups.
+type Hotset struct {
+	cfg      Config
+	hotBytes int64
+}
+
+// NewHotset builds the hot-range generator.
+func NewHotset(cfg Config) *Hotset {
+	if cfg.HotsetPct <= 0 {
+		cfg.HotsetPct = 0.8
+	}
+	if cfg.HotRangePct <= 0 {
+		cfg.HotRangePct = 0.2
+	}
+	return &Hotset{cfg: cfg, hotBytes: int64(float64(cfg.ObjectSize) * cfg.HotRangePct)}
+}
+
+func (h *Hotset) NextRequest(ctx context.Context) (string, int64, int64) {
+	var start int64
+	if rand.Float64() < h.cfg.HotsetPct && h.hotBytes > h.cfg.RangeSize {
+		start = rand.Int63n(h.hotBytes - h.cfg.RangeSize)
+	} else if max := maxStart(h.cfg); max > 0 {
+		start = rand.Int63n(max)
+	}
+	return h.cfg.ObjectKey, start, clampLength(h.cfg, start)
+}
+
+func (h *Hotset) Name() string { return "hotset" }
+func (h *Hotset) Params() string {
+	return fmt.Sprintf("hot_pct=%.2f,range_pct=%.2f", h.cfg.HotsetPct, h.cfg.HotRangePct)
+}